@@ -4,6 +4,10 @@
 
 package mym
 
+import (
+	"math"
+)
+
 func knuadd(u, v float64) (x, y float64) {
 	// Knuth, Seminumerical Algorithms, 3rd ed (1998)
 	x = u + v
@@ -13,7 +17,49 @@ func knuadd(u, v float64) (x, y float64) {
 	return
 }
 
+// knumul -- error-free product x+y=u·v; uses the fused multiply-add
+// where it is implemented in hardware, and Dekker's algorithm otherwise.
 func knumul(u, v float64) (x, y float64) {
+	if fmahw {
+		return knumulFMA(u, v)
+	}
+	return knumulDekker(u, v)
+}
+
+// knumulFMA -- error-free product x+y=u·v based on the fused multiply-add.
+// The error term is zero when the product overflows.
+func knumulFMA(u, v float64) (x, y float64) {
+	x = u * v
+	y = math.FMA(u, v, -x)
+	if math.IsInf(x, 0) {
+		y = 0
+	}
+	return
+}
+
+// knumulDekker -- error-free product x+y=u·v based on Dekker's splitting.
+// The error term is zero when the product overflows.
+func knumulDekker(u, v float64) (x, y float64) {
+	// the splitting u·(2^27+1) overflows for |u|>2^996, so
+	// a large factor is scaled down and the error term is scaled back
+	const big, down, up = 0x1p995, 0x1p-28, 0x1p28
+	x = u * v
+	switch {
+	case math.IsInf(x, 0):
+		y = 0
+	case math.Abs(u) > big:
+		_, y = dekker(u*down, v)
+		y *= up
+	case math.Abs(v) > big:
+		_, y = dekker(u, v*down)
+		y *= up
+	default:
+		_, y = dekker(u, v)
+	}
+	return
+}
+
+func dekker(u, v float64) (x, y float64) {
 	// Knuth, Seminumerical Algorithms, 3rd ed (1998)
 	const C = 134217729 // 2^27+1
 	up, vp := u*C, v*C
@@ -73,3 +119,106 @@ func AccuDot2(m, n int, f, g func(int, int) float64) float64 {
 	}
 	return p + s
 }
+
+// AccuSumSlice -- computes the sum x[0]+x[1]+...+x[len(x)-1] using a compensated summation algorithm.
+// The result is the same as AccuSum(len(x),func(i int) float64 { return x[i] }).
+func AccuSumSlice(x []float64) float64 {
+	// Ogita, Rump, Oishi. Accurate sum and dot product. SIAM Journal on Scientific Computing, 26(6):1955–1988, 2005.
+	var p, q, s float64
+	for _, xi := range x {
+		p, q = knuadd(p, xi)
+		s += q
+	}
+	return p + s
+}
+
+// AccuSumStride -- computes the sum x[0]+x[inc]+...+x[(n-1)·inc] using a compensated summation algorithm.
+// The result is the same as AccuSum(n,func(i int) float64 { return x[i*inc] }).
+func AccuSumStride(n int, x []float64, inc int) float64 {
+	if n <= 0 {
+		return 0
+	}
+	if inc < 1 || (n-1)*inc >= len(x) {
+		panic("mym.AccuSumStride: index")
+	}
+	// Ogita, Rump, Oishi. Accurate sum and dot product. SIAM Journal on Scientific Computing, 26(6):1955–1988, 2005.
+	var p, q, s float64
+	for i := 0; i < n*inc; i += inc {
+		p, q = knuadd(p, x[i])
+		s += q
+	}
+	return p + s
+}
+
+// AccuDotSlice -- computes the inner (dot) product x[0]y[0]+x[1]y[1]+...+x[n-1]y[n-1], n=len(x)=len(y),
+// using a compensated summation algorithm.
+// The result is the same as AccuDot(n,func(i int) float64 { return x[i] },func(i int) float64 { return y[i] }).
+func AccuDotSlice(x, y []float64) float64 {
+	if len(x) != len(y) {
+		panic("mym.AccuDotSlice: len(x) != len(y)")
+	}
+	y = y[:len(x)]
+	// Ogita, Rump, Oishi. Accurate sum and dot product. SIAM Journal on Scientific Computing, 26(6):1955–1988, 2005.
+	var h, p, q, r, s float64
+	for i, xi := range x {
+		h, r = knumul(xi, y[i])
+		p, q = knuadd(p, h)
+		s += q + r
+	}
+	return p + s
+}
+
+// AccuDotStride -- computes the inner (dot) product x[0]y[0]+x[incx]y[incy]+...+x[(n-1)·incx]y[(n-1)·incy]
+// using a compensated summation algorithm.
+// The result is the same as AccuDot(n,func(i int) float64 { return x[i*incx] },func(i int) float64 { return y[i*incy] }).
+func AccuDotStride(n int, x []float64, incx int, y []float64, incy int) float64 {
+	if n <= 0 {
+		return 0
+	}
+	if incx < 1 || (n-1)*incx >= len(x) || incy < 1 || (n-1)*incy >= len(y) {
+		panic("mym.AccuDotStride: index")
+	}
+	// Ogita, Rump, Oishi. Accurate sum and dot product. SIAM Journal on Scientific Computing, 26(6):1955–1988, 2005.
+	var h, p, q, r, s float64
+	for i, j := 0, 0; i < n*incx; i, j = i+incx, j+incy {
+		h, r = knumul(x[i], y[j])
+		p, q = knuadd(p, h)
+		s += q + r
+	}
+	return p + s
+}
+
+// Accu -- represents a compensated accumulator of sums and inner (dot) products.
+// The zero value is an empty sum. Adding the terms f(0),f(1),...,f(n-1) in this order
+// gives the same result as AccuSum(n,f), and adding the products f(0)g(0),...,f(n-1)g(n-1)
+// gives the same result as AccuDot(n,f,g).
+type Accu struct {
+	p, s float64
+}
+
+// Add -- adds the term `x` to the accumulator.
+func (a *Accu) Add(x float64) {
+	var q float64
+	a.p, q = knuadd(a.p, x)
+	a.s += q
+}
+
+// AddProduct -- adds the product x*y to the accumulator.
+func (a *Accu) AddProduct(x, y float64) {
+	var q float64
+	h, r := knumul(x, y)
+	a.p, q = knuadd(a.p, h)
+	a.s += q + r
+}
+
+// Merge -- adds the partial sum accumulated in `b` to the accumulator.
+func (a *Accu) Merge(b Accu) {
+	var q float64
+	a.p, q = knuadd(a.p, b.p)
+	a.s += q + b.s
+}
+
+// Value -- returns the accumulated sum.
+func (a Accu) Value() float64 {
+	return a.p + a.s
+}
@@ -0,0 +1,405 @@
+// Copyright (c) 2019-2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+// TestAccu checks if the streaming accumulator Accu gives the same results
+// as AccuSum and AccuDot, and if merging the partial sums of an ill-conditioned
+// sum (10^k,1,-10^k,...) gives the exact result.
+func TestAccu(t *testing.T) {
+	const n = 10000
+	x := make([]float64, n)
+	y := make([]float64, n)
+	for i := range x {
+		x[i], y[i] = N01(), N01()
+	}
+	//
+	var a, b Accu
+	for i := range x {
+		a.Add(x[i])
+		b.AddProduct(x[i], y[i])
+	}
+	if a.Value() != AccuSum(n, func(i int) float64 { return x[i] }) {
+		t.Fatalf("accu.add != accusum")
+	}
+	if b.Value() != AccuDot(n, func(i int) float64 { return x[i] }, func(i int) float64 { return y[i] }) {
+		t.Fatalf("accu.addproduct != accudot")
+	}
+	//
+	var parts [4]Accu
+	for k := 0; k < 1000; k++ {
+		e := float64(int64(1) << uint(k%50))
+		parts[k%4].Add(e)
+		parts[(k+1)%4].Add(1)
+		parts[(k+2)%4].Add(-e)
+	}
+	var c Accu
+	for _, p := range parts {
+		c.Merge(p)
+	}
+	if c.Value() != 1000 {
+		t.Fatalf("accu.merge: %v != 1000", c.Value())
+	}
+}
+
+// illsum -- returns a shuffled vector of n terms whose exact sum is `s`
+// and whose condition number is about 2^e.
+func illsum(n, e int, s []float64) []float64 {
+	x := append([]float64{}, s...)
+	for len(x)+2 <= n {
+		v := math.Ldexp(U01(), int(U01()*float64(e)))
+		x = append(x, v, -v)
+	}
+	for i := len(x) - 1; i > 0; i-- {
+		j := int(U01() * float64(i+1))
+		x[i], x[j] = x[j], x[i]
+	}
+	return x
+}
+
+// TestAccSum checks if SumK, DotK, and AccSum compute the exact results
+// of extremely ill-conditioned sums and dot products (condition number ~2^400).
+func TestAccSum(t *testing.T) {
+	for trial := 0; trial < 100; trial++ {
+		// the exact sum is a floating point number
+		s := []float64{math.Ldexp(math.Ceil(1000*U01()), -20), math.Ldexp(math.Ceil(1000*U01()), -40)}
+		x := illsum(1000, 400, s)
+		want := s[0] + s[1]
+		f := func(i int) float64 { return x[i] }
+		if sum := AccSum(len(x), f); sum != want {
+			t.Fatalf("accsum: %v != %v", sum, want)
+		}
+		if sum := SumK(len(x), f, 10); sum != want {
+			t.Fatalf("sumk: %v != %v", sum, want)
+		}
+		one := func(int) float64 { return 1 }
+		if dot := DotK(len(x), f, one, 10); dot != want {
+			t.Fatalf("dotk: %v != %v", dot, want)
+		}
+	}
+}
+
+func TestAccSumSpecial(t *testing.T) {
+	const big = math.MaxFloat64
+	inf, nan := math.Inf(+1), math.NaN()
+	for _, c := range []struct {
+		x    []float64
+		want float64
+	}{
+		{[]float64{big / 2, 1}, big / 2},
+		{[]float64{big, -big, 1}, 1},
+		{[]float64{big, 0x1p970, -big}, 0x1p970},
+		{[]float64{big, big / 2, -big}, big / 2},
+		{[]float64{big, big}, inf},
+		{[]float64{-big, -big}, -inf},
+		{[]float64{inf, 1}, inf},
+		{[]float64{1, -inf, -big}, -inf},
+		{[]float64{inf, 1, -inf}, nan},
+		{[]float64{1, nan, 2}, nan},
+		{[]float64{nan, inf}, nan},
+	} {
+		got := AccSum(len(c.x), func(i int) float64 { return c.x[i] })
+		if !(got == c.want || math.IsNaN(got) && math.IsNaN(c.want)) {
+			t.Fatalf("accsum(%v)=%v, want %v", c.x, got, c.want)
+		}
+	}
+	// many large terms
+	x := make([]float64, 1000)
+	for i := range x {
+		x[i] = big / 2000 * float64(1-2*(i%2))
+	}
+	x[999] = 3
+	if got := AccSum(len(x), func(i int) float64 { return x[i] }); got != big/2000+3 {
+		t.Fatalf("accsum=%v, want %v", got, big/2000+3)
+	}
+}
+
+// TestKnumul checks if the FMA-based and Dekker's error-free products agree,
+// and if AccuDot handles the terms close to math.MaxFloat64.
+func TestKnumul(t *testing.T) {
+	for k := 0; k < 100000; k++ {
+		// the product neither overflows nor underflows
+		eu := int(1500*U01()) - 480
+		lo := imax(-480, -900-eu)
+		ev := int(float64(1020-eu-lo)*U01()) + lo
+		u := math.Ldexp(U01(), eu)
+		v := math.Ldexp(U01(), ev)
+		x1, y1 := knumulFMA(u, v)
+		x2, y2 := knumulDekker(u, v)
+		if x1 != x2 || y1 != y2 {
+			t.Fatalf("knumul: u=%v, v=%v, fma=(%v,%v), dekker=(%v,%v)", u, v, x1, y1, x2, y2)
+		}
+	}
+	//
+	f := []float64{math.MaxFloat64, 1.0 / 3, math.MaxFloat64, 0x1.fffffffffffffp511}
+	g := []float64{0.5, 3, -0.5, 0x1.fffffffffffffp511}
+	want := math.FMA(f[3], g[3], 1)
+	if dot := AccuDot(len(f), func(i int) float64 { return f[i] }, func(i int) float64 { return g[i] }); dot != want {
+		t.Fatalf("accudot: %v != %v", dot, want)
+	}
+	for _, mul := range []func(u, v float64) (float64, float64){knumulFMA, knumulDekker} {
+		x, y := mul(math.MaxFloat64, 0.75)
+		if x != 0.75*math.MaxFloat64 || y != math.FMA(math.MaxFloat64, 0.75, -x) {
+			t.Fatalf("knumul: MaxFloat64·0.75 = (%v,%v)", x, y)
+		}
+		x, y = mul(math.MaxFloat64, 2)
+		if !math.IsInf(x, +1) || y != 0 {
+			t.Fatalf("knumul: MaxFloat64·2 = (%v,%v)", x, y)
+		}
+	}
+}
+
+func BenchmarkKnumulFMA(b *testing.B) {
+	x, y := N01(), N01()
+	s := 0.0
+	for i := 0; i < b.N; i++ {
+		h, r := knumulFMA(x, y)
+		s += h + r
+		x += 1
+	}
+	benchsink = s
+}
+
+func BenchmarkKnumulDekker(b *testing.B) {
+	x, y := N01(), N01()
+	s := 0.0
+	for i := 0; i < b.N; i++ {
+		h, r := knumulDekker(x, y)
+		s += h + r
+		x += 1
+	}
+	benchsink = s
+}
+
+func BenchmarkAccuDot(b *testing.B) {
+	x := benchsample(10000)
+	y := benchsample(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchsink = AccuDot(len(x), func(i int) float64 { return x[i] }, func(i int) float64 { return y[i] })
+	}
+}
+
+// BenchmarkAccuDotDekker -- AccuDot with Dekker's error-free product for comparison.
+func BenchmarkAccuDotDekker(b *testing.B) {
+	x := benchsample(10000)
+	y := benchsample(10000)
+	f := func(i int) float64 { return x[i] }
+	g := func(i int) float64 { return y[i] }
+	b.ResetTimer()
+	for k := 0; k < b.N; k++ {
+		var h, p, q, r, s float64
+		for i := 0; i < len(x); i++ {
+			h, r = knumulDekker(f(i), g(i))
+			p, q = knuadd(p, h)
+			s += q + r
+		}
+		benchsink = p + s
+	}
+}
+
+var benchsink float64
+
+// TestCompHorner checks if the error of CompHorner for the polynomial (x-2)^10
+// in expanded form near its root is within the returned bound, and if AccuNorm2
+// and AccuSumSq avoid overflow and underflow.
+func TestCompHorner(t *testing.T) {
+	c := []float64{1024, -5120, 11520, -15360, 13440, -8064, 3360, -960, 180, -20, 1}
+	for k := 0; k < 1000; k++ {
+		x := 2 + math.Ldexp(N01(), -int(20*U01()))
+		p, ebound := CompHorner(len(c), func(i int) float64 { return c[i] }, x)
+		// exact value
+		d := new(big.Float).SetPrec(2000).SetFloat64(x)
+		d.Sub(d, big.NewFloat(2))
+		e := new(big.Float).SetPrec(2000).SetInt64(1)
+		for i := 0; i < 10; i++ {
+			e.Mul(e, d)
+		}
+		err := new(big.Float).SetPrec(2000).SetFloat64(p)
+		err.Sub(err, e)
+		if err.Abs(err).Cmp(big.NewFloat(ebound)) > 0 {
+			t.Fatalf("comphorner: x=%v, p=%v, err=%v > ebound=%v", x, p, err, ebound)
+		}
+	}
+	//
+	v := []float64{3e300, 4e300, 3e-300, 4e-300}
+	if n2 := AccuNorm2(2, func(i int) float64 { return v[i] }); n2 != 5e300 {
+		t.Fatalf("accunorm2: %v != 5e300", n2)
+	}
+	if n2 := AccuNorm2(2, func(i int) float64 { return v[i+2] }); n2 != 5e-300 {
+		t.Fatalf("accunorm2: %v != 5e-300", n2)
+	}
+	w := []float64{math.Ldexp(3, 500), math.Ldexp(4, 500)}
+	if s2 := AccuSumSq(2, func(i int) float64 { return w[i] }); s2 != math.Ldexp(25, 1000) {
+		t.Fatalf("accusumsq: %v != 25·2^1000", s2)
+	}
+}
+
+// TestAccuPar checks if AccuSum2Par and AccuDot2Par return identical results
+// for 1,2,...,8 workers, and if they agree with AccuSum2 and AccuDot2.
+func TestAccuPar(t *testing.T) {
+	const m, n = 123, 456
+	x := make([]float64, m*n)
+	y := make([]float64, m*n)
+	for i := range x {
+		x[i], y[i] = math.Ldexp(N01(), int(40*U01())), N01()
+	}
+	f := func(i, j int) float64 { return x[i*n+j] }
+	g := func(i, j int) float64 { return y[i*n+j] }
+	sum, dot := AccuSum2Par(m, n, f, 1), AccuDot2Par(m, n, f, g, 1)
+	for w := 2; w <= 8; w++ {
+		if s := AccuSum2Par(m, n, f, w); s != sum {
+			t.Fatalf("accusum2par: workers=%v, %v != %v", w, s, sum)
+		}
+		if d := AccuDot2Par(m, n, f, g, w); d != dot {
+			t.Fatalf("accudot2par: workers=%v, %v != %v", w, d, dot)
+		}
+	}
+	if s := AccuSum2(m, n, f); math.Abs(s-sum) > Epsilon*math.Abs(s) {
+		t.Fatalf("accusum2par: %v != %v", sum, s)
+	}
+	if d := AccuDot2(m, n, f, g); math.Abs(d-dot) > Epsilon*math.Abs(d) {
+		t.Fatalf("accudot2par: %v != %v", dot, d)
+	}
+}
+
+// TestReproSum checks if ReproSum and ReproAccu return bit-identical results
+// for permuted and partitioned ill-conditioned sums, and if the results
+// are correctly rounded.
+func TestReproSum(t *testing.T) {
+	for trial := 0; trial < 100; trial++ {
+		s := []float64{U01(), math.Ldexp(U01(), -80), math.Ldexp(N01(), -1070), math.Ldexp(N01(), 1000)}
+		x := illsum(1000, 1020, s)
+		// correctly rounded exact sum
+		e := new(big.Float).SetPrec(4000)
+		for _, xi := range x {
+			e.Add(e, new(big.Float).SetFloat64(xi))
+		}
+		want, _ := e.Float64()
+		sum := ReproSum(len(x), func(i int) float64 { return x[i] })
+		if sum != want {
+			t.Fatalf("reprosum: %v != %v", sum, want)
+		}
+		// reversed order, partitioned into 7 accumulators
+		var parts [7]ReproAccu
+		for i := range x {
+			parts[i%7].Add(x[len(x)-1-i])
+		}
+		var a ReproAccu
+		for _, p := range parts {
+			a.Merge(p)
+		}
+		if a.Value() != sum {
+			t.Fatalf("reproaccu: %v != %v", a.Value(), sum)
+		}
+	}
+	//
+	var a ReproAccu
+	a.Add(math.Inf(+1))
+	if !math.IsInf(a.Value(), +1) {
+		t.Fatalf("reproaccu: %v != +Inf", a.Value())
+	}
+	a.Add(math.Inf(-1))
+	if !math.IsNaN(a.Value()) {
+		t.Fatalf("reproaccu: %v != NaN", a.Value())
+	}
+}
+
+// TestAccuSlice checks if the slice-based and strided entry points
+// give the same results as AccuSum and AccuDot.
+func TestAccuSlice(t *testing.T) {
+	x := benchsample(3000)
+	y := benchsample(3000)
+	f := func(i int) float64 { return x[i] }
+	g := func(i int) float64 { return y[i] }
+	if AccuSumSlice(x) != AccuSum(len(x), f) {
+		t.Fatalf("accusumslice != accusum")
+	}
+	if AccuDotSlice(x, y) != AccuDot(len(x), f, g) {
+		t.Fatalf("accudotslice != accudot")
+	}
+	if AccuSumStride(1000, x, 3) != AccuSum(1000, func(i int) float64 { return x[3*i] }) {
+		t.Fatalf("accusumstride != accusum")
+	}
+	if AccuDotStride(1000, x, 3, y, 2) != AccuDot(1000, func(i int) float64 { return x[3*i] }, func(i int) float64 { return y[2*i] }) {
+		t.Fatalf("accudotstride != accudot")
+	}
+}
+
+func BenchmarkAccuSum(b *testing.B) {
+	x := benchsample(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchsink = AccuSum(len(x), func(i int) float64 { return x[i] })
+	}
+}
+
+func BenchmarkAccuSumSlice(b *testing.B) {
+	x := benchsample(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchsink = AccuSumSlice(x)
+	}
+}
+
+func BenchmarkAccuDotSlice(b *testing.B) {
+	x := benchsample(10000)
+	y := benchsample(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchsink = AccuDotSlice(x, y)
+	}
+}
+
+// TestAccuErr checks if the exact errors of AccuSumErr and AccuDotErr
+// for ill-conditioned sums are within the returned bounds.
+func TestAccuErr(t *testing.T) {
+	for trial := 0; trial < 100; trial++ {
+		x := illsum(1000, 100, []float64{U01(), math.Ldexp(N01(), -70)})
+		y := benchsample(len(x))
+		f := func(i int) float64 { return x[i] }
+		g := func(i int) float64 { return y[i] }
+		//
+		sum, ebound, cond := AccuSumErr(len(x), f)
+		e := new(big.Float).SetPrec(2000).SetFloat64(-sum)
+		for _, xi := range x {
+			e.Add(e, new(big.Float).SetFloat64(xi))
+		}
+		if e.Abs(e).Cmp(big.NewFloat(ebound)) > 0 || !(cond > 1e20) {
+			t.Fatalf("accusumerr: err=%v > ebound=%v, cond=%v", e, ebound, cond)
+		}
+		//
+		dot, ebound, _ := AccuDotErr(len(x), f, g)
+		e.SetFloat64(-dot)
+		for i := range x {
+			e.Add(e, new(big.Float).SetPrec(200).Mul(big.NewFloat(x[i]), big.NewFloat(y[i])))
+		}
+		if e.Abs(e).Cmp(big.NewFloat(ebound)) > 0 {
+			t.Fatalf("accudoterr: err=%v > ebound=%v", e, ebound)
+		}
+	}
+	// cond=+Inf if the sum is zero, including n=0
+	zero := func(int) float64 { return 0 }
+	pm := func(i int) float64 { return float64(1 - 2*(i%2)) }
+	for _, n := range []int{0, 1, 10} {
+		if sum, ebound, cond := AccuSumErr(n, zero); sum != 0 || ebound != 0 || !math.IsInf(cond, +1) {
+			t.Fatalf("accusumerr(n=%d, zero): %v, %v, %v", n, sum, ebound, cond)
+		}
+		if dot, ebound, cond := AccuDotErr(n, zero, pm); dot != 0 || ebound != 0 || !math.IsInf(cond, +1) {
+			t.Fatalf("accudoterr(n=%d, zero): %v, %v, %v", n, dot, ebound, cond)
+		}
+	}
+	if sum, _, cond := AccuSumErr(4, pm); sum != 0 || !math.IsInf(cond, +1) {
+		t.Fatalf("accusumerr(1-1+1-1): %v, %v", sum, cond)
+	}
+	if dot, _, cond := AccuDotErr(4, pm, pm); dot != 4 || cond != 2 {
+		t.Fatalf("accudoterr(pm,pm): %v, %v", dot, cond)
+	}
+}
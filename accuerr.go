@@ -0,0 +1,70 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+import (
+	"math"
+)
+
+// gamma -- returns γ(k) = k·u/(1-k·u), u=2^(-53).
+func gamma(k int) float64 {
+	const u = Epsilon / 2
+	ku := float64(k) * u
+	return ku / (1 - ku)
+}
+
+// AccuSumErr -- computes the sum s=f(0)+f(1)+...+f(n-1) using a compensated summation
+// algorithm (same as AccuSum). Also returns a rigorous bound `ebound` of the absolute
+// error |sum-s| (valid in the absence of underflow and overflow) and the estimated
+// condition number cond = Σ|f(i)|/|sum| (+Inf if sum=0).
+func AccuSumErr(n int, f func(int) float64) (sum, ebound, cond float64) {
+	// Ogita, Rump, Oishi. Accurate sum and dot product. SIAM Journal on Scientific Computing, 26(6):1955–1988, 2005.
+	// |sum-s| ≤ u|s| + γ(n-1)²Σ|f(i)|
+	const u = Epsilon / 2
+	var p, q, s, S float64
+	for i := 0; i < n; i++ {
+		fi := f(i)
+		p, q = knuadd(p, fi)
+		s += q
+		S += math.Abs(fi)
+	}
+	sum = p + s
+	g := gamma(imax(n-1, 0))
+	S /= 1 - g // the computed S has a relative error ≤γ(n-1)
+	ebound = (u*math.Abs(sum) + g*g*S) / (1 - u) * (1 + 4*u)
+	if sum == 0 {
+		cond = math.Inf(+1)
+	} else {
+		cond = S * (1 - g) / math.Abs(sum)
+	}
+	return
+}
+
+// AccuDotErr -- computes the inner (dot) product d=f(0)g(0)+f(1)g(1)+...+f(n-1)g(n-1)
+// using a compensated summation algorithm (same as AccuDot). Also returns a rigorous bound
+// `ebound` of the absolute error |dot-d| (valid in the absence of underflow and overflow)
+// and the estimated condition number cond = 2Σ|f(i)g(i)|/|dot| (+Inf if dot=0).
+func AccuDotErr(n int, f, g func(int) float64) (dot, ebound, cond float64) {
+	// Ogita, Rump, Oishi. Accurate sum and dot product. SIAM Journal on Scientific Computing, 26(6):1955–1988, 2005.
+	// |dot-d| ≤ u|d| + γ(n)²Σ|f(i)g(i)|
+	const u = Epsilon / 2
+	var h, p, q, r, s, S float64
+	for i := 0; i < n; i++ {
+		h, r = knumul(f(i), g(i))
+		p, q = knuadd(p, h)
+		s += q + r
+		S += math.Abs(h)
+	}
+	dot = p + s
+	γ := gamma(n)
+	S /= 1 - γ // the computed S has a relative error ≤γ(n)
+	ebound = (u*math.Abs(dot) + γ*γ*S) / (1 - u) * (1 + 4*u)
+	if dot == 0 {
+		cond = math.Inf(+1)
+	} else {
+		cond = 2 * S * (1 - γ) / math.Abs(dot)
+	}
+	return
+}
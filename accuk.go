@@ -0,0 +1,170 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+import (
+	"math"
+)
+
+// vecsum -- transforms `p` in place into a vector with the same sum,
+// leaving the approximate sum in the last element (error-free vector transformation).
+func vecsum(p []float64) {
+	for i := 1; i < len(p); i++ {
+		p[i], p[i-1] = knuadd(p[i], p[i-1])
+	}
+}
+
+// sumk -- computes the sum of `p` as if in K-fold precision; `p` is overwritten.
+func sumk(p []float64, K int) float64 {
+	n := len(p)
+	if n == 0 {
+		return 0
+	}
+	for k := 1; k < K; k++ {
+		vecsum(p)
+	}
+	s := 0.0
+	for i := 0; i < n-1; i++ {
+		s += p[i]
+	}
+	return s + p[n-1]
+}
+
+// SumK -- computes the sum f(0)+f(1)+...+f(n-1) as if computed in K-fold
+// working precision and then rounded to working precision (K≥1).
+// SumK with K=2 is as accurate as AccuSum. The terms are stored in a temporary vector.
+func SumK(n int, f func(int) float64, K int) float64 {
+	// Ogita, Rump, Oishi. Accurate sum and dot product. SIAM Journal on Scientific Computing, 26(6):1955–1988, 2005.
+	if K < 1 {
+		panic("mym.SumK: K < 1")
+	}
+	p := make([]float64, imax(n, 0))
+	for i := range p {
+		p[i] = f(i)
+	}
+	return sumk(p, K)
+}
+
+// DotK -- computes the inner (dot) product f(0)g(0)+f(1)g(1)+...+f(n-1)g(n-1)
+// as if computed in K-fold working precision and then rounded to working precision (K≥2).
+// DotK with K=2 is as accurate as AccuDot. The products are stored in a temporary vector.
+func DotK(n int, f, g func(int) float64, K int) float64 {
+	// Ogita, Rump, Oishi. Accurate sum and dot product. SIAM Journal on Scientific Computing, 26(6):1955–1988, 2005.
+	if K < 2 {
+		panic("mym.DotK: K < 2")
+	}
+	if n <= 0 {
+		return 0
+	}
+	r := make([]float64, 2*n)
+	var p, h float64
+	p, r[0] = knumul(f(0), g(0))
+	for i := 1; i < n; i++ {
+		h, r[i] = knumul(f(i), g(i))
+		p, r[n+i-1] = knuadd(p, h)
+	}
+	r[2*n-1] = p
+	return sumk(r, K-1)
+}
+
+// AccSum -- computes a faithfully rounded sum f(0)+f(1)+...+f(n-1), that is,
+// the result is either the exact sum or one of its two floating point neighbors;
+// the result is exact if the exact sum is a floating point number.
+// The computing time is proportional to the logarithm of the condition number.
+// The terms are stored in a temporary vector. Returns NaN if a term is NaN or the terms
+// include both +Inf and -Inf, and ±Inf if a term is ±Inf. If the magnitude of a term is
+// close to math.MaxFloat64, the terms are scaled down by a power of two, so that
+// the precision of the subnormal terms may be lost.
+func AccSum(n int, f func(int) float64) float64 {
+	// Rump, Ogita, Oishi. Accurate floating-point summation part I: faithful rounding.
+	// SIAM Journal on Scientific Computing, 31(1):189–224, 2008.
+	p := make([]float64, imax(n, 0))
+	for i := range p {
+		p[i] = f(i)
+	}
+	return accsum(p)
+}
+
+// accsum -- computes a faithfully rounded sum of `p`; `p` is overwritten.
+func accsum(p []float64) float64 {
+	const eps = Epsilon / 2 // 2^(-53)
+	n := len(p)
+	if n == 0 {
+		return 0
+	}
+	inf, μ := 0.0, 0.0
+	for _, pi := range p {
+		switch {
+		case math.IsNaN(pi):
+			return pi
+		case math.IsInf(pi, 0):
+			if inf != 0 && inf != pi {
+				return math.NaN()
+			}
+			inf = pi
+		}
+		μ = math.Max(μ, math.Abs(pi))
+	}
+	if inf != 0 {
+		return inf
+	}
+	// 2^M ≥ n+2
+	twoM := float64(pow2ceil(n + 2))
+	φ := twoM * eps
+	factor := 2 * twoM * twoM * eps
+	// the terms are scaled down by 2^k, so that σ=2^M·nextpow2(μ)≤2^1023
+	_, m := math.Frexp(twoM)
+	_, e := math.Frexp(μ)
+	k := imax(m+e-1024, 0)
+	if k > 0 {
+		for i, pi := range p {
+			p[i] = math.Ldexp(pi, -k)
+		}
+	}
+	for {
+		μ := 0.0
+		for _, pi := range p {
+			μ = math.Max(μ, math.Abs(pi))
+		}
+		if μ == 0 {
+			return 0
+		}
+		σ := twoM * nextpow2(μ)
+		t := 0.0
+		for {
+			// extract the leading parts of `p` (the sum τ is exact)
+			τ := 0.0
+			for i, pi := range p {
+				q := (σ + pi) - σ
+				τ += q
+				p[i] = pi - q
+			}
+			t0 := t
+			t = t0 + τ
+			if t == 0 {
+				// restart with the remaining parts
+				break
+			}
+			if math.Abs(t) >= factor*σ || σ <= Tiny {
+				τ2 := τ - (t - t0)
+				s := 0.0
+				for _, pi := range p {
+					s += pi
+				}
+				return math.Ldexp(t+(τ2+s), k)
+			}
+			σ *= φ
+		}
+	}
+}
+
+// nextpow2 -- the smallest power of two ≥|x|, x≠0.
+func nextpow2(x float64) float64 {
+	frac, exp := math.Frexp(math.Abs(x))
+	if frac == 0.5 {
+		return math.Ldexp(1, exp-1)
+	}
+	return math.Ldexp(1, exp)
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+import (
+	"math"
+)
+
+// AccuSumSq -- computes the sum of squares f(0)²+f(1)²+...+f(n-1)² using
+// a compensated summation algorithm. The terms are scaled by a power of two,
+// so the intermediate results neither overflow nor underflow. The function `f`
+// is called twice for each index.
+func AccuSumSq(n int, f func(int) float64) float64 {
+	p, s, k := sumsq(n, f)
+	return math.Ldexp(p+s, 2*k)
+}
+
+// AccuNorm2 -- computes the Euclidean norm √(f(0)²+f(1)²+...+f(n-1)²) using
+// a compensated summation algorithm. The terms are scaled by a power of two,
+// so the intermediate results neither overflow nor underflow. The function `f`
+// is called twice for each index.
+func AccuNorm2(n int, f func(int) float64) float64 {
+	p, s, k := sumsq(n, f)
+	if !FiniteIs(p) {
+		return p
+	}
+	return math.Ldexp(NewDD(p, s).Sqrt().Float64(), k)
+}
+
+// sumsq -- computes Σ(f(i)·2^(-k))² = p+s, where 2^(k-1)≤max|f(i)|<2^k.
+func sumsq(n int, f func(int) float64) (p, s float64, k int) {
+	μ := 0.0
+	for i := 0; i < n; i++ {
+		μ = math.Max(μ, math.Abs(f(i)))
+	}
+	if μ == 0 || !FiniteIs(μ) {
+		p = μ
+		return
+	}
+	_, k = math.Frexp(μ)
+	//
+	// Ogita, Rump, Oishi. Accurate sum and dot product. SIAM Journal on Scientific Computing, 26(6):1955–1988, 2005.
+	var h, q, r float64
+	for i := 0; i < n; i++ {
+		v := math.Ldexp(f(i), -k)
+		h, r = knumul(v, v)
+		p, q = knuadd(p, h)
+		s += q + r
+	}
+	return
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// parblock -- the number of terms in a block of a parallel reduction.
+const parblock = 1 << 12
+
+// AccuSum2Par -- computes the sum f(0,0)+f(0,1)+...+f(m-1,n-1) using a compensated
+// summation algorithm on `workers` goroutines (runtime.GOMAXPROCS(0) if workers<1).
+// The terms, in row-major order, are partitioned into fixed blocks, and the partial
+// sums of the blocks are merged in order, so the result does not depend on
+// the number of workers. The function `f` must be safe for concurrent use.
+func AccuSum2Par(m, n int, f func(int, int) float64, workers int) float64 {
+	return parreduce(m, n, workers, func(a *Accu, i, j int) {
+		a.Add(f(i, j))
+	})
+}
+
+// AccuDot2Par -- computes the inner (dot) product f(0,0)g(0,0)+f(0,1)g(0,1)+...+f(m-1,n-1)g(m-1,n-1)
+// using a compensated summation algorithm on `workers` goroutines (runtime.GOMAXPROCS(0) if workers<1).
+// The terms, in row-major order, are partitioned into fixed blocks, and the partial
+// sums of the blocks are merged in order, so the result does not depend on
+// the number of workers. The functions `f` and `g` must be safe for concurrent use.
+func AccuDot2Par(m, n int, f, g func(int, int) float64, workers int) float64 {
+	return parreduce(m, n, workers, func(a *Accu, i, j int) {
+		a.AddProduct(f(i, j), g(i, j))
+	})
+}
+
+// parreduce -- accumulates the terms (i,j), 0≤i<m, 0≤j<n, in blocks of `parblock`
+// terms on `workers` goroutines, and merges the partial results in block order.
+func parreduce(m, n, workers int, add func(a *Accu, i, j int)) float64 {
+	if m <= 0 || n <= 0 {
+		return 0
+	}
+	N := m * n
+	nb := (N + parblock - 1) / parblock
+	parts := make([]Accu, nb)
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = imin(workers, nb)
+	//
+	var next int64
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for {
+				b := int(atomic.AddInt64(&next, 1) - 1)
+				if b >= nb {
+					return
+				}
+				k0, k1 := b*parblock, imin((b+1)*parblock, N)
+				i, j := k0/n, k0%n
+				for k := k0; k < k1; k++ {
+					add(&parts[b], i, j)
+					if j++; j == n {
+						i, j = i+1, 0
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	//
+	var a Accu
+	for _, p := range parts {
+		a.Merge(p)
+	}
+	return a.Value()
+}
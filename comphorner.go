@@ -0,0 +1,43 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+import (
+	"math"
+)
+
+// CompHorner -- evaluates the polynomial a(0)+a(1)x+...+a(n-1)x^(n-1) using
+// the compensated Horner scheme, which is as accurate as the Horner scheme
+// computed in twice the working precision. Also returns an a posteriori bound
+// `ebound` of the absolute error of the result `p` (valid in the absence of
+// underflow and overflow).
+//
+// Reference: Graillat, Langlois, Louvet, Algorithms for accurate, validated and fast
+// polynomial evaluation, Japan Journal of Industrial and Applied Mathematics,
+// vol 26 (2-3), pp 191-214 (2009).
+//
+// DOI: https://doi.org/10.1007/BF03186531
+func CompHorner(n int, a func(int) float64, x float64) (p, ebound float64) {
+	if n <= 0 {
+		return 0, 0
+	}
+	const u = Epsilon / 2
+	var π, σ, c, b float64
+	s := a(n - 1)
+	ax := math.Abs(x)
+	for i := n - 2; i >= 0; i-- {
+		s, π = knumul(s, x)
+		s, σ = knuadd(s, a(i))
+		// Horner's scheme for the error polynomials
+		c = c*x + (π + σ)
+		b = b*ax + (math.Abs(π) + math.Abs(σ))
+	}
+	p = s + c
+	//
+	d := float64(n - 1) // degree
+	γ := (4*d + 2) * u / (1 - (4*d+2)*u)
+	ebound = (u*math.Abs(p) + (γ*b + 2*u*u*math.Abs(p))) / (1 - 2*(d+1)*u)
+	return
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+import (
+	"sort"
+)
+
+// convdirect -- the maximum length of the shorter operand
+// for which Convolve uses the direct algorithm.
+const convdirect = 64
+
+// Convolve -- returns the convolution z[k]=Σx[i]·y[k-i] of `x` and `y`.
+// The index bounds of `z` are [xL+yL,xU+yU], where [xL,xU] and [yL,yU] are
+// the index bounds of `x` and `y`. The direct algorithm with compensated sums
+// is used for sparse vectors and when the shorter vector has at most 64 elements;
+// otherwise, the convolution is computed by FFT, so that the absolute error of
+// each element is O(ε·n·max|x|·max|y|).
+func (x RVec) Convolve(y RVec) RVec {
+	xL, xU := x.Ix()
+	yL, yU := y.Ix()
+	if xU < xL || yU < yL {
+		return RVec{}
+	}
+	if x.sparse || y.sparse || imin(xU-xL, yU-yL)+1 <= convdirect {
+		return convexact(x, y)
+	}
+	convcheck(x, y)
+	return convfft(x, y)
+}
+
+// convexact -- the convolution of `x` and `y` by the direct algorithm
+// with compensated sums, regardless of the lengths of `x` and `y`.
+func convexact(x, y RVec) RVec {
+	xL, xU := x.Ix()
+	yL, yU := y.Ix()
+	if xU < xL || yU < yL {
+		return RVec{}
+	}
+	convcheck(x, y)
+	if x.sparse || y.sparse {
+		return convsparse(x, y)
+	}
+	return convdense(x, y)
+}
+
+// convcheck -- panics if the index bounds of the convolution of
+// nonempty `x` and `y` are invalid.
+func convcheck(x, y RVec) {
+	xL, xU := x.Ix()
+	yL, yU := y.Ix()
+	if xL+yL < Mindex || xU+yU > Maxdex {
+		panic(erriib)
+	}
+}
+
+// Correlate -- returns the cross-correlation z[k]=Σx[i+k]·y[i] of `x` and `y`.
+// The index bounds of `z` are [xL-yU,xU-yL], where [xL,xU] and [yL,yU] are
+// the index bounds of `x` and `y`. The algorithm is chosen as in Convolve.
+func (x RVec) Correlate(y RVec) RVec {
+	return x.Convolve(y.reverse())
+}
+
+// reverse -- returns the vector z[i]=y[-i].
+func (y RVec) reverse() RVec {
+	L, U := y.Ix()
+	if U < L {
+		return RVec{}
+	}
+	if y.sparse {
+		z := RVec{sparse: true, lwb: -U, upb: -L}
+		n := len(y.six)
+		z.six = make([]int, n)
+		z.sval = make([]float64, n)
+		for k := range y.six {
+			z.six[n-1-k] = -y.six[k]
+			z.sval[n-1-k] = y.sval[k]
+		}
+		return z
+	}
+	z := Genrvec(-U, -L)
+	for i := L; i <= U; i++ {
+		z.U(-i, y.E(i))
+	}
+	return z
+}
+
+// convdense -- the direct convolution of dense vectors.
+func convdense(x, y RVec) RVec {
+	xL, xU := x.Ix()
+	yL, yU := y.Ix()
+	return rvecgen(xL+yL, (xU-xL)+(yU-yL)+1, func(j int) float64 {
+		k := xL + yL + j
+		var a Accu
+		for i := imax(xL, k-yU); i <= imin(xU, k-yL); i++ {
+			a.AddProduct(x.vec[i-xL], y.vec[k-i-yL])
+		}
+		return a.Value()
+	})
+}
+
+// convsparse -- the direct convolution of vectors, where `x` or `y` is sparse.
+func convsparse(x, y RVec) RVec {
+	xL, xU := x.Ix()
+	yL, yU := y.Ix()
+	xi, xv := x.nonzeros()
+	yi, yv := y.nonzeros()
+	acc := make(map[int]*Accu)
+	for j, i := range xi {
+		for k, l := range yi {
+			a := acc[i+l]
+			if a == nil {
+				a = new(Accu)
+				acc[i+l] = a
+			}
+			a.AddProduct(xv[j], yv[k])
+		}
+	}
+	z := RVec{sparse: true, lwb: xL + yL, upb: xU + yU}
+	for k, a := range acc {
+		if a.Value() != 0 {
+			z.six = append(z.six, k)
+		}
+	}
+	sort.Ints(z.six)
+	z.sval = make([]float64, len(z.six))
+	for k, i := range z.six {
+		z.sval[k] = acc[i].Value()
+	}
+	return z
+}
+
+// convfft -- the FFT-based convolution of dense vectors.
+func convfft(x, y RVec) RVec {
+	xL, xU := x.Ix()
+	yL, yU := y.Ix()
+	nz := (xU - xL) + (yU - yL) + 1
+	n := pow2ceil(nz)
+	a := make([]complex128, n)
+	b := make([]complex128, n)
+	for i, e := range x.vec {
+		a[i] = complex(e, 0)
+	}
+	for i, e := range y.vec {
+		b[i] = complex(e, 0)
+	}
+	plan := NewFFTPlan(len(a))
+	plan.transform(a, a, false)
+	plan.transform(b, b, false)
+	for i := range a {
+		a[i] *= b[i]
+	}
+	plan.transform(a, a, true)
+	return rvecgen(xL+yL, nz, func(k int) float64 { return real(a[k]) / float64(n) })
+}
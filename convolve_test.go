@@ -0,0 +1,90 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+import (
+	"math"
+	"testing"
+)
+
+// randrvec -- returns a vector with random elements and the index bounds [lwb,lwb+n-1].
+func randrvec(lwb, n int) RVec {
+	x := Genrvec(lwb, lwb+n-1)
+	for i := lwb; i < lwb+n; i++ {
+		x.U(i, N01())
+	}
+	return x
+}
+
+func TestConvolve(t *testing.T) {
+	for _, nn := range [][2]int{{1, 1}, {3, 5}, {64, 1000}, {100, 300}, {1000, 1000}} {
+		x := randrvec(-7, nn[0])
+		y := randrvec(3, nn[1])
+		z := x.Convolve(y)
+		c := x.Correlate(y)
+		if L, U := z.Ix(); L != -4 || U != nn[0]+nn[1]-6 {
+			t.Fatalf("convolve: Ix()=%v,%v", L, U)
+		}
+		if L, U := c.Ix(); L != -7-(nn[1]+2) || U != nn[0]-11 {
+			t.Fatalf("correlate: Ix()=%v,%v", L, U)
+		}
+		tol := 1e-12 * math.Sqrt(float64(nn[0]))
+		for k := -4; k <= nn[0]+nn[1]-6; k++ {
+			var a Accu
+			for i := -7; i < nn[0]-7; i++ {
+				a.AddProduct(x.E(i), y.E(k-i))
+			}
+			if math.Abs(z.E(k)-a.Value()) > tol {
+				t.Fatalf("convolve %v: z[%d]=%v, want %v", nn, k, z.E(k), a.Value())
+			}
+		}
+		for k := -7 - (nn[1] + 2); k <= nn[0]-11; k++ {
+			var a Accu
+			for i := 3; i < nn[1]+3; i++ {
+				a.AddProduct(x.E(i+k), y.E(i))
+			}
+			if math.Abs(c.E(k)-a.Value()) > tol {
+				t.Fatalf("correlate %v: c[%d]=%v, want %v", nn, k, c.E(k), a.Value())
+			}
+		}
+	}
+	// sparse
+	var x, y RVec
+	x.U(-1e12, 2)
+	x.U(1e12, 3)
+	y.U(5, 1)
+	y.U(6, -1)
+	z := x.Convolve(y)
+	if L, U := z.Ix(); L != 5-1e12 || U != 6+1e12 || z.E(5-1e12) != 2 || z.E(6-1e12) != -2 ||
+		z.E(5+1e12) != 3 || z.E(6+1e12) != -3 || z.Sumabs() != 10 {
+		t.Fatalf("sparse convolve: Ix()=%v,%v", L, U)
+	}
+	c := x.Correlate(y)
+	if c.E(-6-1e12) != -2 || c.E(-5+1e12) != 3 || c.Sumabs() != 10 {
+		t.Fatal("sparse correlate")
+	}
+}
+
+func TestConvolveBounds(t *testing.T) {
+	convpanic := func(x, y RVec) {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("Convolve: no panic")
+			}
+		}()
+		x.Convolve(y)
+	}
+	var x, y RVec
+	x.U(Maxdex-1, 1)
+	x.U(0, 1)
+	y.U(Maxdex-1, 1)
+	y.U(-1e12, 1)
+	convpanic(x, y)
+	convpanic(y, x)
+	d := randrvec(Maxdex-100, 101)
+	convpanic(d, d)
+	convpanic(d, randrvec(Maxdex-10, 11))
+	convpanic(d.Neg().reverse(), randrvec(Mindex, 11))
+}
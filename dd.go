@@ -0,0 +1,275 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// DD -- represents a double-double number hi+lo, where hi=fl(hi+lo).
+// A double-double number has about 106 significant bits (32 decimal digits)
+// and the same exponent range as float64; the precision degrades for |hi|<2^(-969),
+// where the low-order part becomes subnormal.
+//
+// Reference: Hida, Li, Bailey, Library for Double-Double and Quad-Double
+// Arithmetic, technical report, NERSC Division, LBNL (2007).
+type DD struct {
+	hi, lo float64
+}
+
+// dekadd -- error-free sum x+y=u+v for |u|≥|v| (Dekker's fast two-sum).
+func dekadd(u, v float64) (x, y float64) {
+	x = u + v
+	y = v - (x - u)
+	return
+}
+
+// NewDD -- returns the double-double number hi+lo.
+func NewDD(hi, lo float64) DD {
+	x, y := knuadd(hi, lo)
+	return DD{x, y}
+}
+
+// DDOf -- returns the double-double number x.
+func DDOf(x float64) DD {
+	return DD{x, 0}
+}
+
+// ParseDD -- returns the double-double number nearest to the decimal number `s`.
+func ParseDD(s string) (DD, error) {
+	f, _, err := big.ParseFloat(s, 10, 256, big.ToNearestEven)
+	if err != nil {
+		return DD{}, err
+	}
+	hi, _ := f.Float64()
+	if math.IsInf(hi, 0) {
+		return DD{hi, 0}, nil
+	}
+	lo, _ := f.Sub(f, big.NewFloat(hi)).Float64()
+	return DD{hi, lo}, nil
+}
+
+// Hi -- returns the high-order part of `a`.
+func (a DD) Hi() float64 {
+	return a.hi
+}
+
+// Lo -- returns the low-order part of `a`.
+func (a DD) Lo() float64 {
+	return a.lo
+}
+
+// Float64 -- returns `a` rounded to float64.
+func (a DD) Float64() float64 {
+	return a.hi
+}
+
+// Neg -- returns -a.
+func (a DD) Neg() DD {
+	return DD{-a.hi, -a.lo}
+}
+
+// Abs -- returns |a|.
+func (a DD) Abs() DD {
+	if a.hi < 0 || (a.hi == 0 && a.lo < 0) {
+		return a.Neg()
+	}
+	return a
+}
+
+// ddinf -- returns DD{hi,lo}, or DD{hi,0} if `hi` is infinite or NaN.
+func ddinf(hi, lo float64) DD {
+	if !FiniteIs(hi) {
+		return DD{hi, 0}
+	}
+	return DD{hi, lo}
+}
+
+// Add -- returns a+b.
+func (a DD) Add(b DD) DD {
+	if s := a.hi + b.hi; !FiniteIs(s) {
+		return DD{s, 0}
+	}
+	s, e := knuadd(a.hi, b.hi)
+	t, f := knuadd(a.lo, b.lo)
+	e += t
+	s, e = dekadd(s, e)
+	e += f
+	s, e = dekadd(s, e)
+	return ddinf(s, e)
+}
+
+// Sub -- returns a-b.
+func (a DD) Sub(b DD) DD {
+	return a.Add(b.Neg())
+}
+
+// Mul -- returns a·b.
+func (a DD) Mul(b DD) DD {
+	p, e := knumul(a.hi, b.hi)
+	if !FiniteIs(p) {
+		return DD{p, 0}
+	}
+	e += a.hi*b.lo + a.lo*b.hi
+	p, e = dekadd(p, e)
+	return ddinf(p, e)
+}
+
+// mulf -- returns a·b.
+func (a DD) mulf(b float64) DD {
+	p, e := knumul(a.hi, b)
+	e += a.lo * b
+	p, e = dekadd(p, e)
+	return DD{p, e}
+}
+
+// ldexp -- returns a·2^k.
+func (a DD) ldexp(k int) DD {
+	return DD{math.Ldexp(a.hi, k), math.Ldexp(a.lo, k)}
+}
+
+// Div -- returns a/b.
+func (a DD) Div(b DD) DD {
+	q1 := a.hi / b.hi
+	if !FiniteIs(q1) || !FiniteIs(b.hi) {
+		return DD{q1, 0}
+	}
+	r := a.Sub(b.mulf(q1))
+	q2 := r.hi / b.hi
+	r = r.Sub(b.mulf(q2))
+	q3 := r.hi / b.hi
+	q1, q2 = dekadd(q1, q2)
+	return DD{q1, q2}.Add(DD{q3, 0})
+}
+
+// Sqrt -- returns √a. Returns NaN if a<0.
+func (a DD) Sqrt() DD {
+	if a.hi == 0 {
+		return DD{}
+	}
+	if a.hi < 0 {
+		return DD{math.NaN(), math.NaN()}
+	}
+	if math.IsInf(a.hi, +1) {
+		return a
+	}
+	// one Newton step from the float64 approximation
+	s := math.Sqrt(a.hi)
+	p, e := knumul(s, s)
+	r := a.Sub(DD{p, e})
+	s, t := dekadd(s, r.hi/(2*s))
+	return DD{s, t}
+}
+
+// ddln2 -- ln(2) as a double-double number.
+var ddln2 = DD{6.931471805599452862e-01, 2.319046813846299558e-17}
+
+// Exp -- returns e^a.
+func (a DD) Exp() DD {
+	switch {
+	case math.IsNaN(a.hi):
+		return a
+	case a.hi < -745.1332191019412: // e^a<2^(-1075)
+		return DD{}
+	case a.hi > 709.782712893384: // e^a>MaxFloat64
+		return DD{math.Inf(+1), 0}
+	case a.hi == 0:
+		return DD{1, 0}
+	}
+	// a = m·ln(2) + 2^9·r, |r|≤ln(2)/2^10
+	const k = 9
+	m := math.Floor(a.hi/ddln2.hi + 0.5)
+	r := a.Sub(ddln2.mulf(m)).ldexp(-k)
+	// s = e^r-1 (Taylor series)
+	s := r
+	p := r
+	for i := 2; i <= 20; i++ {
+		p = p.Mul(r)
+		t := p.Div(DD{float64(fact(i)), 0})
+		s = s.Add(t)
+		if math.Abs(t.hi) <= 1e-36*math.Abs(s.hi) {
+			break
+		}
+	}
+	// (e^r-1)² + 2(e^r-1) = e^(2r)-1
+	for i := 0; i < k; i++ {
+		s = s.ldexp(1).Add(s.Mul(s))
+	}
+	s = s.Add(DD{1, 0})
+	if m < -1021 {
+		// the result is subnormal, so it is rounded to float64 once
+		return DD{math.Ldexp(s.hi, int(m)), 0}
+	}
+	s = s.ldexp(int(m))
+	return ddinf(s.hi, s.lo)
+}
+
+// fact -- returns n!, n≤20.
+func fact(n int) int64 {
+	f := int64(1)
+	for i := 2; i <= n; i++ {
+		f *= int64(i)
+	}
+	return f
+}
+
+// Log -- returns ln(a). Returns NaN if a<0.
+func (a DD) Log() DD {
+	switch {
+	case math.IsNaN(a.hi):
+		return a
+	case a.hi < 0:
+		return DD{math.NaN(), math.NaN()}
+	case a.hi == 0:
+		return DD{math.Inf(-1), 0}
+	case math.IsInf(a.hi, +1):
+		return a
+	}
+	// a = 2^k·b, ln(a) = k·ln(2) + ln(b)
+	_, k := math.Frexp(a.hi)
+	b := a.ldexp(-k)
+	// one Newton step x ← x + b·e^(-x) - 1 from the float64 approximation
+	x := DD{math.Log(b.hi), 0}
+	x = x.Add(b.Mul(x.Neg().Exp())).Sub(DD{1, 0})
+	return x.Add(ddln2.mulf(float64(k)))
+}
+
+// Cmp -- returns -1 if a<b, 0 if a=b, and +1 if a>b.
+func (a DD) Cmp(b DD) int {
+	switch {
+	case a.hi < b.hi || (a.hi == b.hi && a.lo < b.lo):
+		return -1
+	case a.hi > b.hi || (a.hi == b.hi && a.lo > b.lo):
+		return +1
+	}
+	return 0
+}
+
+// Eq -- returns true iff a=b.
+func (a DD) Eq(b DD) bool {
+	return a.hi == b.hi && a.lo == b.lo
+}
+
+// Lt -- returns true iff a<b.
+func (a DD) Lt(b DD) bool {
+	return a.hi < b.hi || (a.hi == b.hi && a.lo < b.lo)
+}
+
+// Le -- returns true iff a≤b.
+func (a DD) Le(b DD) bool {
+	return a.hi < b.hi || (a.hi == b.hi && a.lo <= b.lo)
+}
+
+// String -- returns `a` in the decimal exponential format with 32 significant digits.
+func (a DD) String() string {
+	if !FiniteIs(a.hi) {
+		return strconv.FormatFloat(a.hi, 'e', -1, 64)
+	}
+	f := new(big.Float).SetPrec(2200).SetFloat64(a.hi)
+	f.Add(f, new(big.Float).SetFloat64(a.lo))
+	return f.Text('e', 31)
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+// TestDD checks the double-double arithmetic against math/big with
+// a relative accuracy given by `tol`, and the elementary functions
+// against the 40-digit values of e, ln(2), and √2.
+func TestDD(t *testing.T) {
+	const tol = 1.0e-30
+	tobig := func(a DD) *big.Float {
+		f := new(big.Float).SetPrec(2200).SetFloat64(a.Hi())
+		return f.Add(f, new(big.Float).SetFloat64(a.Lo()))
+	}
+	check := func(name string, got DD, want *big.Float) {
+		// relative error, or absolute error for |want|<1 in the case of log
+		d := new(big.Float).SetPrec(2200).Sub(tobig(got), want)
+		if name != "log(exp)" || want.MantExp(nil) > 0 {
+			d.Quo(d, want)
+		}
+		if e, _ := d.Float64(); math.Abs(e) > tol {
+			t.Fatalf("%v: got=%v, want=%v, err=%v", name, got, want.Text('e', 31), e)
+		}
+	}
+	random := func() DD {
+		return NewDD(math.Ldexp(N01(), int(40*U01())-20), math.Ldexp(N01(), -60))
+	}
+	//
+	for k := 0; k < 10000; k++ {
+		a, b := random(), random()
+		x, y := tobig(a), tobig(b)
+		z := new(big.Float).SetPrec(2200)
+		check("add", a.Add(b), z.Add(x, y))
+		check("sub", a.Sub(b), z.Sub(x, y))
+		check("mul", a.Mul(b), z.Mul(x, y))
+		check("div", a.Div(b), new(big.Float).SetPrec(200).Quo(x, y))
+		check("sqrt", a.Abs().Sqrt(), new(big.Float).SetPrec(200).Sqrt(tobig(a.Abs())))
+		if math.Abs(a.Hi()) < 600 {
+			check("log(exp)", a.Exp().Log(), x)
+		}
+		if a.Cmp(b) != x.Cmp(y) || a.Lt(b) != (x.Cmp(y) < 0) || a.Le(b) != (x.Cmp(y) <= 0) {
+			t.Fatalf("cmp: a=%v, b=%v", a, b)
+		}
+	}
+	//
+	parse := func(s string) *big.Float {
+		f, _, _ := big.ParseFloat(s, 10, 200, big.ToNearestEven)
+		return f
+	}
+	e := parse("2.718281828459045235360287471352662497757")
+	ln2 := parse("0.6931471805599453094172321214581765680755")
+	sqrt2 := parse("1.414213562373095048801688724209698078570")
+	check("exp(1)", DDOf(1).Exp(), e)
+	check("log(2)", DDOf(2).Log(), ln2)
+	check("sqrt(2)", DDOf(2).Sqrt(), sqrt2)
+	if s := DDOf(2).Sqrt().String(); s != "1.4142135623730950488016887242097e+00" {
+		t.Fatalf("string: %v", s)
+	}
+	if a, err := ParseDD("2.718281828459045235360287471352662497757"); err != nil || a.Hi() != 2.718281828459045091e+00 || a.Lo() != 1.445646891729250158e-16 {
+		t.Fatalf("parsedd: %v, %v", a, err)
+	}
+}
+
+func TestDDSpecial(t *testing.T) {
+	inf, nan := math.Inf(+1), math.NaN()
+	for _, c := range []struct {
+		name string
+		got  DD
+		want float64
+	}{
+		{"inf+1", DDOf(inf).Add(DDOf(1)), inf},
+		{"1-inf", DDOf(1).Sub(DDOf(inf)), -inf},
+		{"inf-inf", DDOf(inf).Sub(DDOf(inf)), nan},
+		{"max+max", DDOf(math.MaxFloat64).Add(DDOf(math.MaxFloat64)), inf},
+		{"1e200*1e200", DDOf(1e200).Mul(DDOf(1e200)), inf},
+		{"-inf*2", DDOf(-inf).Mul(DDOf(2)), -inf},
+		{"inf*0", DDOf(inf).Mul(DDOf(0)), nan},
+		{"1/0", DDOf(1).Div(DDOf(0)), inf},
+		{"-1/0", DDOf(-1).Div(DDOf(0)), -inf},
+		{"0/0", DDOf(0).Div(DDOf(0)), nan},
+		{"1/inf", DDOf(1).Div(DDOf(inf)), 0},
+		{"inf/2", DDOf(inf).Div(DDOf(2)), inf},
+		{"1e300/1e-300", DDOf(1e300).Div(DDOf(1e-300)), inf},
+		{"exp(-745.2)", DDOf(-745.2).Exp(), 0},
+		{"exp(-inf)", DDOf(-inf).Exp(), 0},
+		{"exp(inf)", DDOf(inf).Exp(), inf},
+		{"exp(709.79)", DDOf(709.79).Exp(), inf},
+		{"exp(709.7828)", DDOf(709.7828).Exp(), inf},
+	} {
+		ok := c.got.Hi() == c.want || (math.IsNaN(c.want) && math.IsNaN(c.got.Hi()))
+		if !ok || c.got.Lo() != 0 {
+			t.Fatalf("%s: got=%v,%v, want %v", c.name, c.got.Hi(), c.got.Lo(), c.want)
+		}
+	}
+	// the largest finite results of Exp
+	for _, x := range []float64{709, 709.78, 709.7827} {
+		got, want := DDOf(x).Exp(), math.Exp(x)
+		if math.Abs(got.Hi()-want) > 1e-15*want || math.IsInf(got.Hi(), 0) {
+			t.Fatalf("exp(%v)=%v, want %v", x, got.Hi(), want)
+		}
+	}
+	// the subnormal range of Exp
+	for _, x := range []float64{-708, -709, -709.5, -720, -740, -745} {
+		got, want := DDOf(x).Exp().Hi(), math.Exp(x)
+		if got == 0 || math.Abs(got-want) > 1e-15*want+0x1p-1074 {
+			t.Fatalf("exp(%v)=%v, want %v", x, got, want)
+		}
+	}
+}
@@ -0,0 +1,449 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+import (
+	"math"
+)
+
+// NormCDF -- returns Φ(x), the N(0,1) cumulative distribution function.
+func NormCDF(x float64) float64 {
+	return math.Erfc(-x/math.Sqrt2) / 2
+}
+
+// NormQuantile -- returns Φ⁻¹(p), the N(0,1) quantile function.
+//
+// Reference: Wichura, Algorithm AS 241: The Percentage Points of the Normal
+// Distribution, Journal of the Royal Statistical Society, Series C,
+// vol 37 (3), pp 477-484 (1988).
+//
+// DOI: https://doi.org/10.2307/2347330
+func NormQuantile(p float64) float64 {
+	if math.IsNaN(p) || p < 0 || p > 1 {
+		return math.NaN()
+	}
+	if p == 0 {
+		return math.Inf(-1)
+	}
+	if p == 1 {
+		return math.Inf(+1)
+	}
+	//
+	q := p - 0.5
+	if math.Abs(q) <= 0.425 {
+		r := 0.180625 - q*q
+		num := (((((((2.5090809287301226727e+3*r+3.3430575583588128105e+4)*r+6.7265770927008700853e+4)*r+
+			4.5921953931549871457e+4)*r+1.3731693765509461125e+4)*r+1.9715909503065514427e+3)*r+
+			1.3314166789178437745e+2)*r + 3.3871328727963666080e+0)
+		den := (((((((5.2264952788528545610e+3*r+2.8729085735721942674e+4)*r+3.9307895800092710610e+4)*r+
+			2.1213794301586595867e+4)*r+5.3941960214247511077e+3)*r+6.8718700749205790830e+2)*r+
+			4.2313330701600911252e+1)*r + 1)
+		return q * num / den
+	}
+	//
+	r := math.Sqrt(-math.Log(math.Min(p, 1-p)))
+	var x float64
+	if r <= 5 {
+		r -= 1.6
+		num := (((((((7.74545014278341407640e-4*r+2.27238449892691845833e-2)*r+2.41780725177450611770e-1)*r+
+			1.27045825245236838258e+0)*r+3.64784832476320460504e+0)*r+5.76949722146069140550e+0)*r+
+			4.63033784615654529590e+0)*r + 1.42343711074968357734e+0)
+		den := (((((((1.05075007164441684324e-9*r+5.47593808499534494600e-4)*r+1.51986665636164571966e-2)*r+
+			1.48103976427480074590e-1)*r+6.89767334985100004550e-1)*r+1.67638483018380384940e+0)*r+
+			2.05319162663775882187e+0)*r + 1)
+		x = num / den
+	} else {
+		r -= 5
+		num := (((((((2.01033439929228813265e-7*r+2.71155556874348757815e-5)*r+1.24266094738807843860e-3)*r+
+			2.65321895265761230930e-2)*r+2.96560571828504891230e-1)*r+1.78482653991729133580e+0)*r+
+			5.46378491116411436990e+0)*r + 6.65790464350110377720e+0)
+		den := (((((((2.04426310338993978564e-15*r+1.42151175831644588870e-7)*r+1.84631831751005468180e-5)*r+
+			7.86869131145613259100e-4)*r+1.48753612908506148525e-2)*r+1.36929880922735805310e-1)*r+
+			5.99832206555887937690e-1)*r + 1)
+		x = num / den
+	}
+	if q < 0 {
+		x = -x
+	}
+	return x
+}
+
+// GammaP -- returns the regularized lower incomplete gamma function
+// P(a,x) = γ(a,x)/Γ(a), a>0, x≥0.
+func GammaP(a, x float64) float64 {
+	P, _ := gammainc(a, x)
+	return P
+}
+
+// GammaQ -- returns the regularized upper incomplete gamma function
+// Q(a,x) = Γ(a,x)/Γ(a) = 1-P(a,x), a>0, x≥0.
+func GammaQ(a, x float64) float64 {
+	_, Q := gammainc(a, x)
+	return Q
+}
+
+// gammainc -- computes P(a,x) and Q(a,x) using the series expansion of P
+// for x<a+1 and the continued fraction for Q otherwise.
+//
+// Reference: Press et al, Numerical Recipes, 3rd ed, section 6.2 (2007).
+func gammainc(a, x float64) (P, Q float64) {
+	if math.IsNaN(a) || math.IsNaN(x) || !(a > 0) || x < 0 {
+		return math.NaN(), math.NaN()
+	}
+	if x == 0 {
+		return 0, 1
+	}
+	if math.IsInf(x, +1) {
+		return 1, 0
+	}
+	lga, _ := math.Lgamma(a)
+	front := math.Exp(-x + a*math.Log(x) - lga)
+	maxit := 1000 + 10*int(math.Sqrt(a))
+	//
+	if x < a+1 {
+		ap, del := a, 1/a
+		sum := del
+		for k := 0; k < maxit; k++ {
+			ap++
+			del *= x / ap
+			sum += del
+			if math.Abs(del) < math.Abs(sum)*Epsilon {
+				break
+			}
+		}
+		P = sum * front
+		Q = 1 - P
+		return
+	}
+	// modified Lentz's method
+	b := x + 1 - a
+	c := 1 / Tiny
+	d := 1 / b
+	h := d
+	for k := 1; k <= maxit; k++ {
+		an := -float64(k) * (float64(k) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < Tiny {
+			d = Tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < Tiny {
+			c = Tiny
+		}
+		d = 1 / d
+		del := d * c
+		h *= del
+		if math.Abs(del-1) <= Epsilon {
+			break
+		}
+	}
+	Q = front * h
+	P = 1 - Q
+	return
+}
+
+// BetaInc -- returns the regularized incomplete beta function
+// I_x(a,b) = B(x;a,b)/B(a,b), a>0, b>0, 0≤x≤1.
+//
+// Reference: Press et al, Numerical Recipes, 3rd ed, section 6.4 (2007).
+func BetaInc(a, b, x float64) float64 {
+	if math.IsNaN(a) || math.IsNaN(b) || math.IsNaN(x) || !(a > 0 && b > 0) || x < 0 || x > 1 {
+		return math.NaN()
+	}
+	if x == 0 || x == 1 {
+		return x
+	}
+	lgab, _ := math.Lgamma(a + b)
+	lga, _ := math.Lgamma(a)
+	lgb, _ := math.Lgamma(b)
+	front := math.Exp(lgab - lga - lgb + a*math.Log(x) + b*math.Log1p(-x))
+	if x < (a+1)/(a+b+2) {
+		return front * betacf(a, b, x) / a
+	}
+	return 1 - front*betacf(b, a, 1-x)/b
+}
+
+// betacf -- evaluates the continued fraction for I_x(a,b) by the modified Lentz's method.
+func betacf(a, b, x float64) float64 {
+	maxit := 1000 + 10*int(math.Sqrt(math.Max(a, b)))
+	qab, qap, qam := a+b, a+1, a-1
+	c := 1.0
+	d := 1 - qab*x/qap
+	if math.Abs(d) < Tiny {
+		d = Tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1; m <= maxit; m++ {
+		mf := float64(m)
+		m2 := 2 * mf
+		aa := mf * (b - mf) * x / ((qam + m2) * (a + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < Tiny {
+			d = Tiny
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < Tiny {
+			c = Tiny
+		}
+		d = 1 / d
+		h *= d * c
+		aa = -(a + mf) * (qab + mf) * x / ((a + m2) * (qap + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < Tiny {
+			d = Tiny
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < Tiny {
+			c = Tiny
+		}
+		d = 1 / d
+		del := d * c
+		h *= del
+		if math.Abs(del-1) <= Epsilon {
+			break
+		}
+	}
+	return h
+}
+
+// gammaincinv -- returns x such that P(a,x)=p (equivalently, Q(a,x)=1-p).
+//
+// Reference: Press et al, Numerical Recipes, 3rd ed, section 6.2.1 (2007).
+func gammaincinv(a, p float64) float64 {
+	if math.IsNaN(a) || math.IsNaN(p) || !(a > 0) || p < 0 || p > 1 {
+		return math.NaN()
+	}
+	if p == 0 {
+		return 0
+	}
+	if p == 1 {
+		return math.Inf(+1)
+	}
+	//
+	a1 := a - 1
+	lga, _ := math.Lgamma(a)
+	var x, lna1, afac float64
+	if a > 1 {
+		lna1 = math.Log(a1)
+		afac = math.Exp(a1*(lna1-1) - lga)
+		x = -NormQuantile(p)
+		x = math.Max(1e-3, a*Cb(1-1/(9*a)-x/(3*math.Sqrt(a))))
+	} else {
+		t := 1 - a*(0.253+a*0.12)
+		if p < t {
+			x = math.Pow(p/t, 1/a)
+		} else {
+			x = 1 - math.Log(1-(p-t)/(1-t))
+		}
+	}
+	// Halley's method
+	for j := 0; j < 20; j++ {
+		if x <= 0 {
+			return 0
+		}
+		P, Q := gammainc(a, x)
+		var err float64
+		if p < 0.5 {
+			err = P - p
+		} else {
+			err = (1 - p) - Q
+		}
+		var t float64
+		if a > 1 {
+			t = afac * math.Exp(-(x-a1)+a1*(math.Log(x)-lna1))
+		} else {
+			t = math.Exp(-x + a1*math.Log(x) - lga)
+		}
+		if t == 0 {
+			break
+		}
+		u := err / t
+		t = u / (1 - 0.5*math.Min(1, u*(a1/x-1)))
+		x -= t
+		if x <= 0 {
+			x = 0.5 * (x + t)
+		}
+		if math.Abs(t) < 1e-15*x {
+			break
+		}
+	}
+	return x
+}
+
+// betaincinv -- returns x such that I_x(a,b)=p.
+//
+// Reference: Press et al, Numerical Recipes, 3rd ed, section 6.4.1 (2007).
+func betaincinv(a, b, p float64) float64 {
+	if math.IsNaN(a) || math.IsNaN(b) || math.IsNaN(p) || !(a > 0 && b > 0) || p < 0 || p > 1 {
+		return math.NaN()
+	}
+	if p == 0 || p == 1 {
+		return p
+	}
+	if p > 0.5 {
+		return 1 - betaincinv(b, a, 1-p)
+	}
+	//
+	a1, b1 := a-1, b-1
+	var x float64
+	if a >= 1 && b >= 1 {
+		y := -NormQuantile(p)
+		al := (y*y - 3) / 6
+		h := 2 / (1/(2*a-1) + 1/(2*b-1))
+		w := (y*math.Sqrt(al+h)/h - (1/(2*b-1)-1/(2*a-1))*(al+5.0/6-2/(3*h)))
+		x = a / (a + b*math.Exp(2*w))
+	} else {
+		lna, lnb := math.Log(a/(a+b)), math.Log(b/(a+b))
+		t := math.Exp(a*lna) / a
+		u := math.Exp(b*lnb) / b
+		w := t + u
+		if p < t/w {
+			x = math.Pow(a*w*p, 1/a)
+		} else {
+			x = 1 - math.Pow(b*w*(1-p), 1/b)
+		}
+	}
+	lgab, _ := math.Lgamma(a + b)
+	lga, _ := math.Lgamma(a)
+	lgb, _ := math.Lgamma(b)
+	afac := lgab - lga - lgb
+	// Halley's method
+	for j := 0; j < 20; j++ {
+		if x == 0 || x == 1 {
+			return x
+		}
+		err := BetaInc(a, b, x) - p
+		t := math.Exp(a1*math.Log(x) + b1*math.Log1p(-x) + afac)
+		if t == 0 {
+			break
+		}
+		u := err / t
+		t = u / (1 - 0.5*math.Min(1, u*(a1/x-b1/(1-x))))
+		x -= t
+		if x <= 0 {
+			x = 0.5 * (x + t)
+		}
+		if x >= 1 {
+			x = 0.5 * (x + t + 1)
+		}
+		if math.Abs(t) < 1e-15*x && j > 0 {
+			break
+		}
+	}
+	return x
+}
+
+// StudentCDF -- returns the cumulative distribution function at `t` of
+// Student's t-distribution with ν>0 degrees of freedom.
+func StudentCDF(t, ν float64) float64 {
+	if math.IsNaN(t) || !(ν > 0) {
+		return math.NaN()
+	}
+	if math.IsInf(t, 0) {
+		return math.Max(0, math.Copysign(1, t))
+	}
+	tail := BetaInc(ν/2, 0.5, ν/(ν+t*t)) / 2
+	if t > 0 {
+		return 1 - tail
+	}
+	return tail
+}
+
+// StudentQuantile -- returns the quantile function at `p` of
+// Student's t-distribution with ν>0 degrees of freedom.
+func StudentQuantile(p, ν float64) float64 {
+	if math.IsNaN(p) || p < 0 || p > 1 || !(ν > 0) {
+		return math.NaN()
+	}
+	tail := math.Min(p, 1-p)
+	var t2 float64
+	if 2*tail < 0.5 {
+		x := betaincinv(ν/2, 0.5, 2*tail)
+		t2 = ν * (1 - x) / x
+	} else {
+		y := betaincinv(0.5, ν/2, 1-2*tail)
+		t2 = ν * y / (1 - y)
+	}
+	t := math.Sqrt(t2)
+	if p < 0.5 {
+		return -t
+	}
+	return t
+}
+
+// ChiSqCDF -- returns the cumulative distribution function at `x` of
+// the χ² distribution with k>0 degrees of freedom.
+func ChiSqCDF(x, k float64) float64 {
+	if x < 0 {
+		return 0
+	}
+	return GammaP(k/2, x/2)
+}
+
+// ChiSqQuantile -- returns the quantile function at `p` of
+// the χ² distribution with k>0 degrees of freedom.
+func ChiSqQuantile(p, k float64) float64 {
+	return 2 * gammaincinv(k/2, p)
+}
+
+// FisherCDF -- returns the cumulative distribution function at `x` of
+// the F-distribution with d1>0 and d2>0 degrees of freedom.
+func FisherCDF(x, d1, d2 float64) float64 {
+	if x < 0 {
+		return 0
+	}
+	if math.IsInf(x, +1) {
+		return 1
+	}
+	return BetaInc(d1/2, d2/2, d1*x/(d1*x+d2))
+}
+
+// FisherQuantile -- returns the quantile function at `p` of
+// the F-distribution with d1>0 and d2>0 degrees of freedom.
+func FisherQuantile(p, d1, d2 float64) float64 {
+	y := betaincinv(d1/2, d2/2, p)
+	return d2 * y / (d1 * (1 - y))
+}
+
+// GammaCDF -- returns the cumulative distribution function at `x` of
+// the gamma distribution with the shape k>0 and the scale θ>0.
+func GammaCDF(x, k, θ float64) float64 {
+	if !(θ > 0) {
+		return math.NaN()
+	}
+	if x < 0 {
+		return 0
+	}
+	return GammaP(k, x/θ)
+}
+
+// GammaQuantile -- returns the quantile function at `p` of
+// the gamma distribution with the shape k>0 and the scale θ>0.
+func GammaQuantile(p, k, θ float64) float64 {
+	if !(θ > 0) {
+		return math.NaN()
+	}
+	return θ * gammaincinv(k, p)
+}
+
+// BetaCDF -- returns the cumulative distribution function at `x` of
+// the beta distribution with the shapes a>0 and b>0.
+func BetaCDF(x, a, b float64) float64 {
+	if x < 0 {
+		return 0
+	}
+	if x > 1 {
+		return 1
+	}
+	return BetaInc(a, b, x)
+}
+
+// BetaQuantile -- returns the quantile function at `p` of
+// the beta distribution with the shapes a>0 and b>0.
+func BetaQuantile(p, a, b float64) float64 {
+	return betaincinv(a, b, p)
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+import (
+	"math"
+	"testing"
+)
+
+// TestDistrib checks the distribution functions against reference values
+// computed in high precision, closed-form special cases, and the identity
+// F(F⁻¹(p))=p, each with a relative accuracy given by `tol`.
+func TestDistrib(t *testing.T) {
+	const tol = 1.0e-12
+	check := func(name string, got, want float64) {
+		if math.Abs(got-want) > tol*math.Abs(want) {
+			t.Fatalf("%v: got=%v, want=%v", name, got, want)
+		}
+	}
+	//
+	check("normcdf(1.96)", NormCDF(1.96), 0.97500210485177952)
+	check("normcdf(-1)", NormCDF(-1), 0.15865525393145705)
+	check("normcdf(-10)", NormCDF(-10), 7.6198530241605260e-24)
+	check("normquantile(0.975)", NormQuantile(0.975), 1.9599639845400543)
+	check("normquantile(1e-10)", NormQuantile(1e-10), -6.3613409024040557)
+	check("factormad", 1/NormQuantile(0.75), FactorMAD)
+	check("chisqquantile(0.95,1)", ChiSqQuantile(0.95, 1), 3.8414588206941250)
+	check("chisqquantile(0.95,10)", ChiSqQuantile(0.95, 10), 18.307038053275146)
+	check("studentquantile(0.975,10)", StudentQuantile(0.975, 10), 2.2281388519862744)
+	check("studentquantile(0.975,1)", StudentQuantile(0.975, 1), 12.706204736174704)
+	check("fisherquantile(0.95,5,10)", FisherQuantile(0.95, 5, 10), 3.3258345304130112)
+	//
+	for _, x := range []float64{0.001, 0.1, 0.5, 1, 2, 5, 10, 30} {
+		check("gammap(1,x)", GammaP(1, x), -math.Expm1(-x))
+		check("gammaq(1,x)", GammaQ(1, x), math.Exp(-x))
+		check("gammap(1/2,x)", GammaP(0.5, x), math.Erf(math.Sqrt(x)))
+		check("gammaq(2,x)", GammaQ(2, x), (1+x)*math.Exp(-x))
+		check("studentcdf(x,1)", StudentCDF(-x, 1), 0.5-math.Atan(x)/math.Pi)
+		check("studentcdf(x,2)", StudentCDF(x, 2), 0.5+x/(2*math.Sqrt(2+x*x)))
+	}
+	for _, x := range []float64{0.001, 0.1, 0.3, 0.5, 0.7, 0.9, 0.999} {
+		check("betainc(3.5,1,x)", BetaInc(3.5, 1, x), math.Pow(x, 3.5))
+		check("betainc(1,2.5,x)", BetaInc(1, 2.5, x), -math.Expm1(2.5*math.Log1p(-x)))
+		check("betainc(1/2,1/2,x)", BetaInc(0.5, 0.5, x), 2/math.Pi*math.Asin(math.Sqrt(x)))
+	}
+	//
+	for _, p := range []float64{1e-12, 1e-6, 0.01, 0.1, 0.3, 0.5, 0.7, 0.9, 0.99, 0.999999} {
+		check("normcdf(normquantile(p))", NormCDF(NormQuantile(p)), p)
+		for _, ν := range []float64{0.5, 1, 3, 10, 100} {
+			check("studentcdf(studentquantile(p))", StudentCDF(StudentQuantile(p, ν), ν), p)
+			check("chisqcdf(chisqquantile(p))", ChiSqCDF(ChiSqQuantile(p, ν), ν), p)
+			check("fishercdf(fisherquantile(p))", FisherCDF(FisherQuantile(p, ν, 7), ν, 7), p)
+			check("gammacdf(gammaquantile(p))", GammaCDF(GammaQuantile(p, ν, 2), ν, 2), p)
+			check("betacdf(betaquantile(p))", BetaCDF(BetaQuantile(p, ν, 2.5), ν, 2.5), p)
+		}
+	}
+}
@@ -0,0 +1,495 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+import (
+	"container/list"
+	"math"
+	"sync"
+)
+
+// FFTPlan -- represents the precomputed factorization and twiddle factors
+// for the discrete Fourier transforms of a fixed length n. Lengths with prime
+// factors ≤13 use the mixed-radix Cooley–Tukey algorithm; other lengths use
+// Bluestein's algorithm. An FFTPlan is safe for concurrent use.
+//
+// Reference: Frigo, Johnson, The Design and Implementation of FFTW3,
+// Proceedings of the IEEE, vol 93 (2), pp 216-231 (2005).
+//
+// DOI: https://doi.org/10.1109/JPROC.2004.840301
+type FFTPlan struct {
+	n    int
+	fac  []int        // the radices
+	tw   []complex128 // e^(-2πik/n), k=0,1,...,n-1
+	blue *bluestein   // non-nil if n has a prime factor >13
+}
+
+// bluestein -- the chirp sequence and its transform for Bluestein's algorithm.
+type bluestein struct {
+	w    []complex128 // e^(-iπk²/n), k=0,1,...,n-1
+	b    []complex128 // the transform of the conjugate chirp, padded to length m
+	plan *FFTPlan     // the plan of length m=2^k≥2n-1
+}
+
+// fftcachesize -- the maximum total size of cached plans (in complex128 elements).
+// The plans larger than fftcachesize/8 are not cached.
+const fftcachesize = 1 << 22
+
+// fftcache -- the cache of recently used plans.
+var fftcache struct {
+	sync.Mutex
+	plans map[int]*list.Element // the elements of lru
+	lru   *list.List            // *FFTPlan, the most recently used first
+	size  int                   // the total size of the plans in lru
+}
+
+// NewFFTPlan -- returns the plan for the transforms of length `n`.
+// Recently used plans are cached (up to 64 MiB in total), so repeated calls
+// with the same length are cheap; the plans longer than about 2^19 are not cached.
+// Panics if n<1.
+func NewFFTPlan(n int) *FFTPlan {
+	if n < 1 {
+		panic("mym.NewFFTPlan: n<1")
+	}
+	c := &fftcache
+	c.Lock()
+	if e, ok := c.plans[n]; ok {
+		c.lru.MoveToFront(e)
+		c.Unlock()
+		return e.Value.(*FFTPlan)
+	}
+	c.Unlock()
+	// the plan is created without the lock, since genbluestein calls NewFFTPlan
+	p := genfftplan(n)
+	if p.size() > fftcachesize/8 {
+		return p
+	}
+	c.Lock()
+	defer c.Unlock()
+	if e, ok := c.plans[n]; ok {
+		c.lru.MoveToFront(e)
+		return e.Value.(*FFTPlan)
+	}
+	if c.plans == nil {
+		c.plans = make(map[int]*list.Element)
+		c.lru = list.New()
+	}
+	c.plans[n] = c.lru.PushFront(p)
+	c.size += p.size()
+	for c.size > fftcachesize {
+		e := c.lru.Back()
+		c.lru.Remove(e)
+		q := e.Value.(*FFTPlan)
+		delete(c.plans, q.n)
+		c.size -= q.size()
+	}
+	return p
+}
+
+// size -- returns the number of complex128 elements stored by `p`.
+func (p *FFTPlan) size() int {
+	if p.blue != nil {
+		return len(p.blue.w) + len(p.blue.b) + p.blue.plan.size()
+	}
+	return len(p.tw)
+}
+
+// genfftplan -- creates the plan for the transforms of length `n`.
+func genfftplan(n int) *FFTPlan {
+	p := &FFTPlan{n: n}
+	m := n
+	for m%4 == 0 {
+		p.fac = append(p.fac, 4)
+		m /= 4
+	}
+	for m%2 == 0 {
+		p.fac = append(p.fac, 2)
+		m /= 2
+	}
+	for _, r := range []int{3, 5, 7, 11, 13} {
+		for m%r == 0 {
+			p.fac = append(p.fac, r)
+			m /= r
+		}
+	}
+	if m > 1 {
+		p.fac = nil
+		p.blue = genbluestein(n)
+		return p
+	}
+	p.tw = make([]complex128, n)
+	for k := 0; k < n; k++ {
+		s, c := sincos2pi(k, n)
+		p.tw[k] = complex(c, -s)
+	}
+	return p
+}
+
+// sincos2pi -- returns sin(2πk/n) and cos(2πk/n); the argument is reduced to [-π,π].
+func sincos2pi(k, n int) (s, c float64) {
+	k %= n
+	if 2*k > n {
+		k -= n
+	} else if 2*k < -n {
+		k += n
+	}
+	return math.Sincos(2 * math.Pi * float64(k) / float64(n))
+}
+
+// genbluestein -- creates the chirp data for Bluestein's algorithm of length `n`.
+func genbluestein(n int) *bluestein {
+	m := pow2ceil(2*n - 1)
+	bs := &bluestein{plan: NewFFTPlan(m)}
+	bs.w = make([]complex128, n)
+	bs.b = make([]complex128, m)
+	for k := 0; k < n; k++ {
+		// e^(-iπk²/n) = e^(-2πi(k² mod 2n)/(2n))
+		s, c := sincos2pi(int((int64(k)*int64(k))%int64(2*n)), 2*n)
+		bs.w[k] = complex(c, -s)
+		bs.b[k] = complex(c, s)
+		if k > 0 {
+			bs.b[m-k] = complex(c, s)
+		}
+	}
+	bs.plan.transform(bs.b, bs.b, false)
+	return bs
+}
+
+// Len -- returns the length of the transforms of `p`.
+func (p *FFTPlan) Len() int {
+	return p.n
+}
+
+// FFT -- computes the discrete Fourier transform dst[k]=Σsrc[j]·e^(-2πijk/n).
+// `dst` and `src` may be the same slice. Panics if len(dst)≠n or len(src)≠n.
+func (p *FFTPlan) FFT(dst, src []complex128) {
+	if len(dst) != p.n || len(src) != p.n {
+		panic("mym.FFTPlan.FFT: invalid length")
+	}
+	p.transform(dst, src, false)
+}
+
+// IFFT -- computes the inverse discrete Fourier transform
+// dst[k]=(1/n)Σsrc[j]·e^(2πijk/n). `dst` and `src` may be the same slice.
+// Panics if len(dst)≠n or len(src)≠n.
+func (p *FFTPlan) IFFT(dst, src []complex128) {
+	if len(dst) != p.n || len(src) != p.n {
+		panic("mym.FFTPlan.IFFT: invalid length")
+	}
+	p.transform(dst, src, true)
+	s := 1 / float64(p.n)
+	for k := range dst {
+		dst[k] *= complex(s, 0)
+	}
+}
+
+// transform -- computes the forward or the inverse (`inv`, not normalized)
+// discrete Fourier transform; `dst` and `src` may be the same slice.
+func (p *FFTPlan) transform(dst, src []complex128, inv bool) {
+	if p.blue != nil {
+		p.bluestein(dst, src, inv)
+		return
+	}
+	if p.n == 1 {
+		dst[0] = src[0]
+		return
+	}
+	if &dst[0] == &src[0] || inv {
+		tmp := make([]complex128, p.n)
+		copy(tmp, src)
+		src = tmp
+	}
+	if inv {
+		// the inverse transform is conj(FFT(conj(x)))
+		conj(src)
+		p.work(dst, src, 1, 0, p.n)
+		conj(dst)
+		return
+	}
+	p.work(dst, src, 1, 0, p.n)
+}
+
+// conj -- replaces the elements of `x` with their complex conjugates.
+func conj(x []complex128) {
+	for k, e := range x {
+		x[k] = complex(real(e), -imag(e))
+	}
+}
+
+// work -- the recursive decimation-in-time step: computes in `out` the transform
+// of length m=n/fstride of the elements in[0], in[fstride], in[2·fstride], ...,
+// using the radices fac[f], fac[f+1], ...
+func (p *FFTPlan) work(out, in []complex128, fstride, f, m int) {
+	r := p.fac[f]
+	m /= r
+	if m == 1 {
+		for k := 0; k < r; k++ {
+			out[k] = in[k*fstride]
+		}
+	} else {
+		for q := 0; q < r; q++ {
+			p.work(out[q*m:], in[q*fstride:], fstride*r, f+1, m)
+		}
+	}
+	switch r {
+	case 2:
+		bfly2(out, fstride, m, p.tw)
+	case 4:
+		bfly4(out, fstride, m, p.tw)
+	default:
+		bflygen(out, fstride, r, m, p.tw)
+	}
+}
+
+// bfly2 -- the radix-2 butterflies.
+func bfly2(out []complex128, fstride, m int, tw []complex128) {
+	a, b := out[:m], out[m:2*m]
+	for k := 0; k < m; k++ {
+		t := b[k] * tw[k*fstride]
+		b[k] = a[k] - t
+		a[k] += t
+	}
+}
+
+// bfly4 -- the radix-4 butterflies.
+func bfly4(out []complex128, fstride, m int, tw []complex128) {
+	for k := 0; k < m; k++ {
+		s0 := out[k+m] * tw[k*fstride]
+		s1 := out[k+2*m] * tw[2*k*fstride]
+		s2 := out[k+3*m] * tw[3*k*fstride]
+		s5 := out[k] - s1
+		out[k] += s1
+		s3 := s0 + s2
+		s4 := s0 - s2
+		out[k+2*m] = out[k] - s3
+		out[k] += s3
+		// i·s4
+		is4 := complex(-imag(s4), real(s4))
+		out[k+m] = s5 - is4
+		out[k+3*m] = s5 + is4
+	}
+}
+
+// bflygen -- the butterflies of an odd prime radix `r`.
+func bflygen(out []complex128, fstride, r, m int, tw []complex128) {
+	n := len(tw)
+	var buf [13]complex128
+	s := buf[:r]
+	for u := 0; u < m; u++ {
+		for q := 0; q < r; q++ {
+			s[q] = out[u+q*m]
+		}
+		for q, k := 0, u; q < r; q, k = q+1, k+m {
+			t := s[0]
+			j := 0
+			for q1 := 1; q1 < r; q1++ {
+				j += fstride * k
+				if j >= n {
+					j %= n
+				}
+				t += s[q1] * tw[j]
+			}
+			out[k] = t
+		}
+	}
+}
+
+// bluestein -- computes the transform of a length with a large prime factor
+// as a convolution of power-of-two length (Bluestein's algorithm).
+func (p *FFTPlan) bluestein(dst, src []complex128, inv bool) {
+	bs := p.blue
+	n, m := p.n, bs.plan.n
+	a := make([]complex128, m)
+	for k := 0; k < n; k++ {
+		x := src[k]
+		if inv {
+			// the inverse transform is conj(FFT(conj(x)))
+			x = complex(real(x), -imag(x))
+		}
+		a[k] = x * bs.w[k]
+	}
+	bs.plan.transform(a, a, false)
+	for k := range a {
+		a[k] *= bs.b[k]
+	}
+	bs.plan.transform(a, a, true)
+	s := complex(1/float64(m), 0)
+	for k := 0; k < n; k++ {
+		y := a[k] * bs.w[k] * s
+		if inv {
+			y = complex(real(y), -imag(y))
+		}
+		dst[k] = y
+	}
+}
+
+// FFT -- returns the discrete Fourier transform X[k]=Σx[j]·e^(-2πijk/n), n=len(x).
+func FFT(x []complex128) []complex128 {
+	X := make([]complex128, len(x))
+	if len(x) > 0 {
+		NewFFTPlan(len(x)).transform(X, x, false)
+	}
+	return X
+}
+
+// IFFT -- returns the inverse discrete Fourier transform x[j]=(1/n)ΣX[k]·e^(2πijk/n), n=len(X).
+func IFFT(X []complex128) []complex128 {
+	x := make([]complex128, len(X))
+	if len(X) > 0 {
+		NewFFTPlan(len(X)).IFFT(x, X)
+	}
+	return x
+}
+
+// RFFT -- returns the elements X[0],X[1],...,X[n/2] of the discrete Fourier transform
+// of a real sequence `x`, n=len(x); the other elements are X[n-k]=conj(X[k]).
+func RFFT(x []float64) []complex128 {
+	n := len(x)
+	if n == 0 {
+		return nil
+	}
+	if oddis(n) {
+		z := make([]complex128, n)
+		for j, xj := range x {
+			z[j] = complex(xj, 0)
+		}
+		NewFFTPlan(n).transform(z, z, false)
+		return z[:n/2+1]
+	}
+	// the transform of z[j]=x[2j]+i·x[2j+1] of length h=n/2 gives
+	// the transforms E and O of the even and odd elements of `x`
+	h := n / 2
+	z := make([]complex128, h)
+	for j := range z {
+		z[j] = complex(x[2*j], x[2*j+1])
+	}
+	NewFFTPlan(h).transform(z, z, false)
+	X := make([]complex128, h+1)
+	for k := 0; k <= h; k++ {
+		zk, zc := z[k%h], z[(h-k)%h]
+		zc = complex(real(zc), -imag(zc))
+		E := (zk + zc) / 2
+		O := (zk - zc) / 2i
+		s, c := sincos2pi(k, n)
+		X[k] = E + complex(c, -s)*O
+	}
+	return X
+}
+
+// IRFFT -- returns the real sequence x of length `n` with RFFT(x)=X, that is,
+// the inverse transform of X[0],X[1],...,X[n/2] extended by X[n-k]=conj(X[k]).
+// The imaginary parts of X[0] (and X[n/2] for an even `n`) are ignored.
+// Panics if len(X)≠n/2+1.
+func IRFFT(X []complex128, n int) []float64 {
+	if n < 0 || len(X) != n/2+1 {
+		panic("mym.IRFFT: len(X)≠n/2+1")
+	}
+	if n == 0 {
+		return nil
+	}
+	x := make([]float64, n)
+	if oddis(n) {
+		z := make([]complex128, n)
+		z[0] = complex(real(X[0]), 0)
+		for k := 1; k <= n/2; k++ {
+			z[k] = X[k]
+			z[n-k] = complex(real(X[k]), -imag(X[k]))
+		}
+		NewFFTPlan(n).IFFT(z, z)
+		for j := range x {
+			x[j] = real(z[j])
+		}
+		return x
+	}
+	// reconstruct Z[k]=E[k]+i·O[k] and invert the transform of length h=n/2
+	h := n / 2
+	z := make([]complex128, h)
+	X0, Xh := complex(real(X[0]), 0), complex(real(X[h]), 0)
+	for k := 0; k < h; k++ {
+		xk, xc := X[k], X[h-k]
+		if k == 0 {
+			xk, xc = X0, Xh
+		}
+		xc = complex(real(xc), -imag(xc))
+		E := (xk + xc) / 2
+		s, c := sincos2pi(k, n)
+		O := (xk - xc) / 2 * complex(c, s)
+		z[k] = E + 1i*O
+	}
+	NewFFTPlan(h).IFFT(z, z)
+	for j, zj := range z {
+		x[2*j], x[2*j+1] = real(zj), imag(zj)
+	}
+	return x
+}
+
+// DCT2 -- returns the discrete cosine transform of type II
+// Y[k]=2Σx[j]·cos(π(2j+1)k/(2n)), n=len(x) (FFTW's REDFT10).
+//
+// Reference: Makhoul, A fast cosine transform in one and two dimensions,
+// IEEE Transactions on Acoustics, Speech, and Signal Processing,
+// vol 28 (1), pp 27-34 (1980).
+//
+// DOI: https://doi.org/10.1109/TASSP.1980.1163351
+func DCT2(x []float64) []float64 {
+	n := len(x)
+	if n == 0 {
+		return nil
+	}
+	// v = x[0],x[2],x[4],...,x[5],x[3],x[1]
+	v := make([]complex128, n)
+	for j := 0; 2*j < n; j++ {
+		v[j] = complex(x[2*j], 0)
+	}
+	for j := 0; 2*j+1 < n; j++ {
+		v[n-1-j] = complex(x[2*j+1], 0)
+	}
+	NewFFTPlan(n).transform(v, v, false)
+	Y := make([]float64, n)
+	for k := range Y {
+		// Y[k] = 2·Re(e^(-iπk/(2n))·V[k])
+		s, c := sincos2pi(k, 4*n)
+		Y[k] = 2 * (c*real(v[k]) + s*imag(v[k]))
+	}
+	return Y
+}
+
+// DCT3 -- returns the discrete cosine transform of type III
+// Y[k]=X[0]+2ΣX[j]·cos(πj(2k+1)/(2n)), j=1,...,n-1, n=len(X) (FFTW's REDFT01).
+// DCT3(DCT2(x))=2n·x.
+func DCT3(X []float64) []float64 {
+	n := len(X)
+	if n == 0 {
+		return nil
+	}
+	// V[k] = e^(iπk/(2n))·(X[k]-i·X[n-k]), X[n]=0
+	v := make([]complex128, n)
+	for k := range v {
+		xc := 0.0
+		if k > 0 {
+			xc = X[n-k]
+		}
+		s, c := sincos2pi(k, 4*n)
+		v[k] = complex(c, s) * complex(X[k], -xc)
+	}
+	NewFFTPlan(n).transform(v, v, true)
+	Y := make([]float64, n)
+	for j := 0; 2*j < n; j++ {
+		Y[2*j] = real(v[j])
+	}
+	for j := 0; 2*j+1 < n; j++ {
+		Y[2*j+1] = real(v[n-1-j])
+	}
+	return Y
+}
+
+// pow2ceil -- the smallest power of two ≥n.
+func pow2ceil(n int) int {
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+// naivedft -- the discrete Fourier transform of `x` computed by definition.
+func naivedft(x []complex128, inv bool) []complex128 {
+	n := len(x)
+	sign := -1
+	if inv {
+		sign = 1
+	}
+	X := make([]complex128, n)
+	c := make([]float64, n)
+	s := make([]float64, n)
+	for k := range X {
+		for j := range x {
+			s[j], c[j] = sincos2pi(sign*((j*k)%n), n)
+		}
+		// (xr+i·xi)(c+i·s) = (xr·c-xi·s) + i(xr·s+xi·c)
+		re := AccuDot(2*n, func(j int) float64 {
+			if j < n {
+				return real(x[j])
+			}
+			return -imag(x[j-n])
+		}, func(j int) float64 {
+			if j < n {
+				return c[j]
+			}
+			return s[j-n]
+		})
+		im := AccuDot(2*n, func(j int) float64 {
+			if j < n {
+				return real(x[j])
+			}
+			return imag(x[j-n])
+		}, func(j int) float64 {
+			if j < n {
+				return s[j]
+			}
+			return c[j-n]
+		})
+		X[k] = complex(re, im)
+	}
+	return X
+}
+
+func TestFFT(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 11, 12, 13, 15, 16, 17, 30, 64, 97, 100, 128, 210, 243, 1000, 1021} {
+		x := make([]complex128, n)
+		r := make([]float64, n)
+		for j := range x {
+			x[j] = complex(N01(), N01())
+			r[j] = N01()
+		}
+		tol := 1e-13 * math.Sqrt(float64(n)) * math.Log2(float64(2*n))
+		//
+		X := FFT(x)
+		want := naivedft(x, false)
+		for k := range X {
+			if cmplx.Abs(X[k]-want[k]) > tol*math.Sqrt(float64(n)) {
+				t.Fatalf("fft(n=%d): X[%d]=%v, want %v", n, k, X[k], want[k])
+			}
+		}
+		want = naivedft(x, true)
+		Y := IFFT(x)
+		for k := range Y {
+			if cmplx.Abs(Y[k]*complex(float64(n), 0)-want[k]) > tol*math.Sqrt(float64(n)) {
+				t.Fatalf("ifft(n=%d): Y[%d]=%v, want %v", n, k, Y[k], want[k]/complex(float64(n), 0))
+			}
+		}
+		y := IFFT(X)
+		for j := range y {
+			if cmplx.Abs(y[j]-x[j]) > tol {
+				t.Fatalf("ifft(fft(x)) (n=%d): y[%d]=%v, want %v", n, j, y[j], x[j])
+			}
+		}
+		// real transforms
+		z := make([]complex128, n)
+		for j := range z {
+			z[j] = complex(r[j], 0)
+		}
+		want = naivedft(z, false)
+		R := RFFT(r)
+		if len(R) != n/2+1 {
+			t.Fatalf("rfft(n=%d): len=%d", n, len(R))
+		}
+		for k := range R {
+			if cmplx.Abs(R[k]-want[k]) > tol*math.Sqrt(float64(n)) {
+				t.Fatalf("rfft(n=%d): R[%d]=%v, want %v", n, k, R[k], want[k])
+			}
+		}
+		rr := IRFFT(R, n)
+		for j := range rr {
+			if math.Abs(rr[j]-r[j]) > tol {
+				t.Fatalf("irfft(n=%d): r[%d]=%v, want %v", n, j, rr[j], r[j])
+			}
+		}
+		// cosine transforms
+		D := DCT2(r)
+		for k := range D {
+			f := func(j int) float64 { return 2 * math.Cos(math.Pi*float64((2*j+1)*k)/float64(2*n)) }
+			d := AccuDot(n, func(j int) float64 { return r[j] }, f)
+			if math.Abs(D[k]-d) > tol*math.Sqrt(float64(n)) {
+				t.Fatalf("dct2(n=%d): D[%d]=%v, want %v", n, k, D[k], d)
+			}
+		}
+		E := DCT3(r)
+		for k := range E {
+			f := func(j int) float64 {
+				if j == 0 {
+					return 1
+				}
+				return 2 * math.Cos(math.Pi*float64(j*(2*k+1))/float64(2*n))
+			}
+			d := AccuDot(n, func(j int) float64 { return r[j] }, f)
+			if math.Abs(E[k]-d) > tol*math.Sqrt(float64(n)) {
+				t.Fatalf("dct3(n=%d): E[%d]=%v, want %v", n, k, E[k], d)
+			}
+		}
+		for j, e := range DCT3(D) {
+			if math.Abs(e/float64(2*n)-r[j]) > tol {
+				t.Fatalf("dct3(dct2(x)) (n=%d): %v, want %v", n, e/float64(2*n), r[j])
+			}
+		}
+	}
+	//
+	p := NewFFTPlan(12)
+	if p.Len() != 12 || NewFFTPlan(12) != p {
+		t.Fatal("plan cache")
+	}
+	// the cache is bounded by the total size, and the evicted plans remain valid
+	for k := 0; k < 30; k++ {
+		NewFFTPlan(49152 + 12*k)
+	}
+	fftcache.Lock()
+	size, cached := 0, fftcache.plans[12] != nil
+	for e := fftcache.lru.Front(); e != nil; e = e.Next() {
+		size += e.Value.(*FFTPlan).size()
+	}
+	fftcache.Unlock()
+	if size > fftcachesize || size != fftcache.size || cached {
+		t.Fatalf("plan cache: size=%v, cached(12)=%v", size, cached)
+	}
+	x := []complex128{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+	y := make([]complex128, 12)
+	p.FFT(y, x)
+	p.IFFT(y, y)
+	for j := range x {
+		if cmplx.Abs(y[j]-x[j]) > 1e-14 {
+			t.Fatalf("evicted plan: %v", y)
+		}
+	}
+	// the long plans are not cached
+	for _, n := range []int{1 << 20, 100003} {
+		NewFFTPlan(n)
+		fftcache.Lock()
+		cached = fftcache.plans[n] != nil
+		fftcache.Unlock()
+		if cached {
+			t.Fatalf("plan cache: the plan of length %v is cached", n)
+		}
+	}
+}
+
+func BenchmarkFFT1024(b *testing.B) { benchFFT(b, 1024) }
+func BenchmarkFFT1000(b *testing.B) { benchFFT(b, 1000) }
+func BenchmarkFFT1021(b *testing.B) { benchFFT(b, 1021) }
+
+func benchFFT(b *testing.B, n int) {
+	p := NewFFTPlan(n)
+	x := make([]complex128, n)
+	for j := range x {
+		x[j] = complex(N01(), N01())
+	}
+	b.ResetTimer()
+	for it := 0; it < b.N; it++ {
+		p.FFT(x, x)
+	}
+}
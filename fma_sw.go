@@ -0,0 +1,11 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+//go:build !amd64 && !arm64 && !ppc64 && !ppc64le && !s390x && !riscv64
+// +build !amd64,!arm64,!ppc64,!ppc64le,!s390x,!riscv64
+
+package mym
+
+// fmahw -- true iff math.FMA is implemented in hardware on this architecture.
+const fmahw = false
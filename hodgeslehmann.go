@@ -0,0 +1,183 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+import (
+	"math"
+	"sort"
+)
+
+// HodgesLehmann -- computes the one-sample Hodges–Lehmann estimator of
+// location for a sample `x`, that is, the median of the Walsh averages
+// (x[i]+x[j])/2, i≤j. Returns NaN if `x` is empty or contains NaNs.
+//
+// Reference: Monahan, Algorithm 616: Fast computation of the Hodges-Lehmann
+// location estimator, ACM TOMS, vol 10 (3), pp 265-270 (1984).
+//
+// DOI: https://doi.org/10.1145/1271.319414
+func HodgesLehmann(x []float64) float64 {
+	n := len(x)
+	if n == 0 || nanis(x) {
+		return math.NaN()
+	}
+	//
+	a := make([]float64, n)
+	copy(a, x)
+	sort.Float64s(a)
+	w := walsh{a}
+	lo, hi := kthmedians(w, n*(n+1)/2)
+	return lo + (hi-lo)/2
+}
+
+// HodgesLehmann2 -- computes the two-sample Hodges–Lehmann estimator of
+// the shift between samples `x` and `y`, that is, the median of the
+// differences x[i]-y[j]. Returns NaN if `x` or `y` is empty or contains NaNs.
+//
+// Reference: Johnson, Mizoguchi, Selecting the Kth element in X+Y and X1+X2+...+Xm,
+// SIAM Journal on Computing, vol 7 (2), pp 147-153 (1978).
+//
+// DOI: https://doi.org/10.1137/0207013
+func HodgesLehmann2(x, y []float64) float64 {
+	m, n := len(x), len(y)
+	if m == 0 || n == 0 || nanis(x) || nanis(y) {
+		return math.NaN()
+	}
+	//
+	a := make([]float64, m)
+	copy(a, x)
+	sort.Float64s(a)
+	b := make([]float64, n)
+	for j, yj := range y {
+		b[j] = -yj
+	}
+	sort.Float64s(b)
+	lo, hi := kthmedians(xplusy{a, b}, m*n)
+	return lo + (hi-lo)/2
+}
+
+// nanis -- returns true iff `x` contains NaNs.
+func nanis(x []float64) bool {
+	for _, xi := range x {
+		if math.IsNaN(xi) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedmat -- a matrix with nondecreasing rows and columns. Row i
+// occupies the columns first(i)...cols()-1.
+type sortedmat interface {
+	rows() int
+	cols() int
+	first(i int) int
+	at(i, j int) float64
+}
+
+// walsh -- the Walsh averages (a[i]+a[j])/2, i≤j, for a sorted `a`.
+type walsh struct {
+	a []float64
+}
+
+func (w walsh) rows() int           { return len(w.a) }
+func (w walsh) cols() int           { return len(w.a) }
+func (w walsh) first(i int) int     { return i }
+func (w walsh) at(i, j int) float64 { return w.a[i]/2 + w.a[j]/2 }
+
+// xplusy -- the sums a[i]+b[j] for sorted `a` and `b`.
+type xplusy struct {
+	a, b []float64
+}
+
+func (s xplusy) rows() int           { return len(s.a) }
+func (s xplusy) cols() int           { return len(s.b) }
+func (s xplusy) first(i int) int     { return 0 }
+func (s xplusy) at(i, j int) float64 { return s.a[i] + s.b[j] }
+
+// kthmedians -- returns the low and high medians of the `N` elements of `s`.
+func kthmedians(s sortedmat, N int) (lo, hi float64) {
+	if oddis(N) {
+		lo = kthsmallest(s, (N-1)/2)
+		hi = lo
+	} else {
+		lo = kthsmallest(s, N/2-1)
+		hi = kthsmallest(s, N/2)
+	}
+	return
+}
+
+// kthsmallest -- returns the k-th smallest (k=0,1,...) element of `s`.
+// The algorithm keeps a column interval [L[i],R[i]] of candidates in each row
+// and shrinks it by partitioning around a randomly selected candidate, which
+// takes O(n log n) expected time for an n-by-n matrix.
+func kthsmallest(s sortedmat, k int) float64 {
+	m, n := s.rows(), s.cols()
+	L := make([]int, m)
+	R := make([]int, m)
+	P := make([]int, m)
+	Q := make([]int, m)
+	for i := 0; i < m; i++ {
+		L[i], R[i] = s.first(i), n-1
+	}
+	//
+	for {
+		// select a random candidate as a pivot
+		total := 0
+		for i := 0; i < m; i++ {
+			if L[i] <= R[i] {
+				total += R[i] - L[i] + 1
+			}
+		}
+		r := int(U01() * float64(total))
+		if r >= total {
+			r = total - 1
+		}
+		var pivot float64
+		for i := 0; i < m; i++ {
+			if L[i] <= R[i] {
+				c := R[i] - L[i] + 1
+				if r < c {
+					pivot = s.at(i, L[i]+r)
+					break
+				}
+				r -= c
+			}
+		}
+		// P[i] -- the first column in row i with s[i,j]≥pivot
+		// Q[i] -- the first column in row i with s[i,j]>pivot
+		nlt, nle := 0, 0
+		j := n
+		for i := 0; i < m; i++ {
+			j = imax(j, s.first(i))
+			for j > s.first(i) && s.at(i, j-1) >= pivot {
+				j--
+			}
+			P[i] = j
+			nlt += j - s.first(i)
+		}
+		j = n
+		for i := 0; i < m; i++ {
+			j = imax(j, s.first(i))
+			for j > s.first(i) && s.at(i, j-1) > pivot {
+				j--
+			}
+			Q[i] = j
+			nle += j - s.first(i)
+		}
+		//
+		switch {
+		case k < nlt:
+			for i := 0; i < m; i++ {
+				R[i] = imin(R[i], P[i]-1)
+			}
+		case k >= nle:
+			for i := 0; i < m; i++ {
+				L[i] = imax(L[i], Q[i])
+			}
+		default:
+			return pivot
+		}
+	}
+}
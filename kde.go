@@ -0,0 +1,290 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+import (
+	"math"
+)
+
+// Kernel -- specifies the smoothing kernel of a kernel density estimate.
+// All kernels are scaled to unit variance, so the bandwidth is
+// the standard deviation of the kernel.
+type Kernel int
+
+const (
+	// Gaussian -- the N(0,1) kernel.
+	Gaussian Kernel = iota
+	// Epanechnikov -- the kernel 3/(4√5)·(1-u²/5), |u|<√5.
+	Epanechnikov
+)
+
+// eval -- returns K(u).
+func (k Kernel) eval(u float64) float64 {
+	switch k {
+	case Gaussian:
+		const c = 0.398942280401432677939946059934381868475858631164935 // 1/√(2π)
+		return c * math.Exp(-u*u/2)
+	case Epanechnikov:
+		const c = 0.335410196624968454461376050309412730940011916097195 // 3/(4√5)
+		if math.Abs(u) < math.Sqrt(5) {
+			return c * (1 - u*u/5)
+		}
+		return 0
+	}
+	panic("mym.Kernel: unknown kernel")
+}
+
+// support -- returns the half-width of the numerical support of K.
+func (k Kernel) support() float64 {
+	if k == Epanechnikov {
+		return math.Sqrt(5)
+	}
+	return 8.5
+}
+
+// KDE -- returns the kernel density estimate at `t` for a sample `x`
+// with the bandwidth `h` and the kernel `k`.
+func KDE(x []float64, h float64, k Kernel, t float64) float64 {
+	n := len(x)
+	if n == 0 || !(h > 0) {
+		return math.NaN()
+	}
+	s := AccuSum(n, func(i int) float64 { return k.eval((t - x[i]) / h) })
+	return s / (float64(n) * h)
+}
+
+// KDEGrid -- returns the kernel density estimate for a sample `x` with
+// the bandwidth `h` and the kernel `k` at `m` equally spaced points
+// lo=t[0]<t[1]<...<t[m-1]=hi. The sample is linearly binned onto the grid
+// and the binned counts are convolved with the kernel using the FFT,
+// which takes O(n+m log m) time. The samples farther than the kernel support
+// from [lo,hi] do not contribute to f and are skipped; f is still normalized
+// by the full sample size n, so that f[j] approximates KDE(x,h,k,t[j])
+// and the estimate does not integrate to 1 over [lo,hi] unless the grid
+// covers the sample.
+func KDEGrid(x []float64, h float64, k Kernel, lo, hi float64, m int) (t, f []float64) {
+	if m < 2 || !(lo < hi) {
+		panic("mym.KDEGrid: invalid grid")
+	}
+	t = make([]float64, m)
+	f = make([]float64, m)
+	δ := (hi - lo) / float64(m-1)
+	for j := range t {
+		t[j] = lo + float64(j)*δ
+	}
+	t[m-1] = hi
+	n := len(x)
+	if n == 0 || !(h > 0) {
+		for j := range f {
+			f[j] = math.NaN()
+		}
+		return
+	}
+	//
+	// the grid is extended by L points on both sides to cover the kernel support
+	L := int(math.Ceil(k.support() * h / δ))
+	M := m + 2*L
+	P := pow2ceil(M)
+	a := make([]complex128, P)
+	for _, xi := range x {
+		u := (xi-lo)/δ + float64(L)
+		if !(u >= 0 && u <= float64(M-1)) {
+			continue
+		}
+		j := int(u)
+		if j == M-1 {
+			j--
+		}
+		w := u - float64(j)
+		a[j] += complex(1-w, 0)
+		a[j+1] += complex(w, 0)
+	}
+	//
+	b := make([]complex128, P)
+	c := 1 / (float64(n) * h)
+	for j := 0; j <= L && j < P; j++ {
+		kj := c * k.eval(float64(j)*δ/h)
+		b[j] = complex(kj, 0)
+		if j > 0 {
+			b[P-j] = complex(kj, 0)
+		}
+	}
+	//
+	plan := NewFFTPlan(len(a))
+	plan.transform(a, a, false)
+	plan.transform(b, b, false)
+	for i := range a {
+		a[i] *= b[i]
+	}
+	plan.transform(a, a, true)
+	for j := range f {
+		f[j] = math.Max(0, real(a[j+L])/float64(P))
+	}
+	return
+}
+
+// BwSilverman -- returns Silverman's rule-of-thumb bandwidth
+// 0.9·min(sd,IQR/1.34)·n^(-1/5) for a sample `x` (same as R's bw.nrd0).
+func BwSilverman(x []float64) float64 {
+	n := len(x)
+	if n < 2 {
+		return math.NaN()
+	}
+	s := robscale(x, 1.34)
+	return 0.9 * s * math.Pow(float64(n), -0.2)
+}
+
+// BwMAD -- returns Silverman's rule-of-thumb bandwidth with the scale
+// estimated by the MAD, 0.9·FactorMAD·MAD·n^(-1/5), for a sample `x`.
+func BwMAD(x []float64) float64 {
+	n := len(x)
+	if n < 2 {
+		return math.NaN()
+	}
+	_, mad := MedianMAD(x)
+	return 0.9 * FactorMAD * mad * math.Pow(float64(n), -0.2)
+}
+
+// BwSJ -- returns the Sheather–Jones "solve-the-equation" plug-in
+// bandwidth for a sample `x` and the Gaussian kernel. The pairwise
+// distances are binned into 1000 bins.
+//
+// Reference: Sheather, Jones, A Reliable Data-Based Bandwidth Selection Method
+// for Kernel Density Estimation, Journal of the Royal Statistical Society,
+// Series B, vol 53 (3), pp 683-690 (1991).
+//
+// DOI: https://doi.org/10.1111/j.2517-6161.1991.tb01857.x
+func BwSJ(x []float64) float64 {
+	const nb = 1000
+	n := len(x)
+	if n < 2 {
+		return math.NaN()
+	}
+	nf := float64(n)
+	//
+	// binned pairwise distances
+	xmin, xmax := x[0], x[0]
+	for _, xi := range x {
+		xmin, xmax = math.Min(xmin, xi), math.Max(xmax, xi)
+	}
+	d := 1.01 * (xmax - xmin) / nb
+	if !(d > 0) {
+		return math.NaN()
+	}
+	bin := make([]float64, nb)
+	for _, xi := range x {
+		bin[imin(int((xi-xmin)/d), nb-1)]++
+	}
+	cnt := make([]float64, nb)
+	for i := 0; i < nb; i++ {
+		if bin[i] == 0 {
+			continue
+		}
+		cnt[0] += bin[i] * (bin[i] - 1) / 2
+		for j := i + 1; j < nb; j++ {
+			cnt[j-i] += bin[i] * bin[j]
+		}
+	}
+	//
+	// estimates of the functionals ∫(f⁽⁴⁾)f and ∫(f⁽⁶⁾)f
+	const c = 2.50662827463100050241576528481104525300698674060994 // √(2π)
+	phi := func(h float64, order int) float64 {
+		s := AccuSum(nb, func(i int) float64 {
+			δ := Sq(float64(i) * d / h)
+			if δ >= 1000 {
+				return 0
+			}
+			if order == 4 {
+				return cnt[i] * math.Exp(-δ/2) * (δ*δ - 6*δ + 3)
+			}
+			return cnt[i] * math.Exp(-δ/2) * (δ*δ*δ - 15*δ*δ + 45*δ - 15)
+		})
+		if order == 4 {
+			return (2*s + 3*nf) / (nf * (nf - 1) * math.Pow(h, 5) * c)
+		}
+		return (2*s - 15*nf) / (nf * (nf - 1) * math.Pow(h, 7) * c)
+	}
+	//
+	scale := robscale(x, 1.349)
+	a := 1.24 * scale * math.Pow(nf, -1.0/7)
+	b := 1.23 * scale * math.Pow(nf, -1.0/9)
+	c1 := 1 / (2 * math.Sqrt(math.Pi) * nf)
+	TD := -phi(b, 6)
+	if !(TD > 0) || !FiniteIs(TD) {
+		return math.NaN()
+	}
+	α2 := 1.357 * math.Pow(phi(a, 4)/TD, 1.0/7)
+	if !FiniteIs(α2) {
+		return math.NaN()
+	}
+	fSD := func(h float64) float64 {
+		return math.Pow(c1/phi(α2*math.Pow(h, 5.0/7), 4), 0.2) - h
+	}
+	//
+	hmax := 1.144 * scale * math.Pow(nf, -0.2)
+	lo, hi := 0.1*hmax, hmax
+	flo, fhi := fSD(lo), fSD(hi)
+	for itry := 1; flo*fhi > 0; itry++ {
+		if itry > 99 {
+			return math.NaN()
+		}
+		if oddis(itry) {
+			hi *= 1.2
+			fhi = fSD(hi)
+		} else {
+			lo /= 1.2
+			flo = fSD(lo)
+		}
+	}
+	// bisection
+	for hi-lo > SqrtEps*hi {
+		mid := lo + (hi-lo)/2
+		fmid := fSD(mid)
+		if (fmid < 0) == (flo < 0) {
+			lo, flo = mid, fmid
+		} else {
+			hi = mid
+		}
+	}
+	return lo + (hi-lo)/2
+}
+
+// robscale -- returns min(sd,IQR/d) for a sample `x`,
+// or a positive scale if the minimum is zero.
+func robscale(x []float64, d float64) float64 {
+	n := len(x)
+	_, vari := MeanVar(x)
+	sd := math.Sqrt(vari)
+	//
+	y := make([]float64, n)
+	copy(y, x)
+	iqr := quantile7(y, 0.75) - quantile7(y, 0.25)
+	s := math.Min(sd, iqr/d)
+	if s > 0 {
+		return s
+	}
+	if sd > 0 {
+		return sd
+	}
+	if x[0] != 0 {
+		return math.Abs(x[0])
+	}
+	return 1
+}
+
+// quantile7 -- returns the `p`-quantile of a sample `y` (Hyndman–Fan type 7).
+// The elements of `y` are reordered.
+func quantile7(y []float64, p float64) float64 {
+	n := len(y)
+	h := float64(n-1) * p
+	k := int(math.Floor(h))
+	select489(y, 0, n-1, k)
+	q := y[k]
+	if k+1 < n && h > float64(k) {
+		select489(y, 0, n-1, k+1)
+		q += (h - float64(k)) * (y[k+1] - q)
+	}
+	return q
+}
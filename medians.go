@@ -10,14 +10,29 @@ import (
 
 // Medians -- returns the low and high medians of a sample `x`.
 func Medians(x []float64) (lomed, himed float64) {
+	return MediansBuf(x, make([]float64, len(x)))
+}
+
+// MediansBuf -- same as Medians, but uses `buf` as a scratch buffer
+// instead of allocating a copy of `x`. It panics if len(buf) < len(x).
+func MediansBuf(x, buf []float64) (lomed, himed float64) {
+	if len(buf) < len(x) {
+		panic("mym.MediansBuf: len(buf) < len(x)")
+	}
+	y := buf[:len(x)]
+	copy(y, x)
+	return MediansInPlace(y)
+}
+
+// MediansInPlace -- same as Medians, but reorders the elements of `x`
+// instead of allocating a copy.
+func MediansInPlace(x []float64) (lomed, himed float64) {
 	if len(x) == 0 {
 		lomed, himed = math.NaN(), math.NaN()
 		return
 	}
 	//
-	y := make([]float64, len(x))
-	copy(y, x)
-	lomed, himed = medians(y)
+	lomed, himed = medians(x)
 	return
 }
 
@@ -26,20 +41,35 @@ const FactorMAD = 1.48260221850560186054707652936042343132670320259031
 
 // MedianMAD -- computes the median (`med`) and the median absolute deviation (`mad`) of a sample `x`.
 func MedianMAD(x []float64) (med, mad float64) {
+	return MedianMADBuf(x, make([]float64, len(x)))
+}
+
+// MedianMADBuf -- same as MedianMAD, but uses `buf` as a scratch buffer
+// instead of allocating a copy of `x`. It panics if len(buf) < len(x).
+func MedianMADBuf(x, buf []float64) (med, mad float64) {
+	if len(buf) < len(x) {
+		panic("mym.MedianMADBuf: len(buf) < len(x)")
+	}
+	y := buf[:len(x)]
+	copy(y, x)
+	return MedianMADInPlace(y)
+}
+
+// MedianMADInPlace -- same as MedianMAD, but overwrites `x` with
+// the absolute deviations from the median instead of allocating a copy.
+func MedianMADInPlace(x []float64) (med, mad float64) {
 	if len(x) == 0 {
 		med, mad = math.NaN(), math.NaN()
 		return
 	}
 	//
-	y := make([]float64, len(x))
-	copy(y, x)
-	lo, hi := medians(y)
+	lo, hi := medians(x)
 	med = lo + (hi-lo)/2
 	//
-	for k, yk := range y {
-		y[k] = math.Abs(yk - med)
+	for k, xk := range x {
+		x[k] = math.Abs(xk - med)
 	}
-	lo, hi = medians(y)
+	lo, hi = medians(x)
 	mad = lo + (hi-lo)/2
 	return
 }
@@ -52,6 +82,23 @@ func MedianMAD(x []float64) (med, mad float64) {
 //    s[3] - upper hinge
 //    s[4] - maximum
 func Summary5(x []float64) (s [5]float64) {
+	return Summary5Buf(x, make([]float64, len(x)))
+}
+
+// Summary5Buf -- same as Summary5, but uses `buf` as a scratch buffer
+// instead of allocating a copy of `x`. It panics if len(buf) < len(x).
+func Summary5Buf(x, buf []float64) (s [5]float64) {
+	if len(buf) < len(x) {
+		panic("mym.Summary5Buf: len(buf) < len(x)")
+	}
+	y := buf[:len(x)]
+	copy(y, x)
+	return Summary5InPlace(y)
+}
+
+// Summary5InPlace -- same as Summary5, but reorders the elements of `x`
+// instead of allocating a copy.
+func Summary5InPlace(x []float64) (s [5]float64) {
 	var (
 		n, m, k int
 	)
@@ -61,8 +108,7 @@ func Summary5(x []float64) (s [5]float64) {
 		return
 	}
 	//
-	y := make([]float64, n)
-	copy(y, x)
+	y := x
 	//
 	n1 := n - 1
 	select489(y, 0, n1, 0)
@@ -0,0 +1,120 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+import (
+	"testing"
+)
+
+// TestMediansVariants checks if the copying, buffered, and in-place variants
+// of Medians, MedianMAD, and Summary5 return identical results for n=1(1)100.
+func TestMediansVariants(t *testing.T) {
+	buf := make([]float64, 100)
+	for n := 1; n <= 100; n++ {
+		x := make([]float64, n)
+		for i := range x {
+			x[i] = N01()
+		}
+		y := make([]float64, n)
+		//
+		lo, hi := Medians(x)
+		lob, hib := MediansBuf(x, buf)
+		copy(y, x)
+		loi, hii := MediansInPlace(y)
+		if !(lo == lob && lo == loi && hi == hib && hi == hii) {
+			t.Fatalf("medians: n=%v", n)
+		}
+		//
+		med, mad := MedianMAD(x)
+		medb, madb := MedianMADBuf(x, buf)
+		copy(y, x)
+		medi, madi := MedianMADInPlace(y)
+		if !(med == medb && med == medi && mad == madb && mad == madi) {
+			t.Fatalf("medianmad: n=%v", n)
+		}
+		//
+		s := Summary5(x)
+		sb := Summary5Buf(x, buf)
+		copy(y, x)
+		si := Summary5InPlace(y)
+		for k := range s {
+			if !(f64EQ(s[k], sb[k]) && f64EQ(s[k], si[k])) {
+				t.Fatalf("summary5: n=%v, k=%v", n, k)
+			}
+		}
+	}
+}
+
+func benchsample(n int) []float64 {
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = N01()
+	}
+	return x
+}
+
+func BenchmarkMedianMAD(b *testing.B) {
+	x := benchsample(10000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		MedianMAD(x)
+	}
+}
+
+func BenchmarkMedianMADBuf(b *testing.B) {
+	x := benchsample(10000)
+	buf := make([]float64, len(x))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		MedianMADBuf(x, buf)
+	}
+}
+
+func BenchmarkMedianMADInPlace(b *testing.B) {
+	x := benchsample(10000)
+	y := make([]float64, len(x))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		copy(y, x)
+		b.StartTimer()
+		MedianMADInPlace(y)
+	}
+}
+
+func BenchmarkSummary5(b *testing.B) {
+	x := benchsample(10000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Summary5(x)
+	}
+}
+
+func BenchmarkSummary5Buf(b *testing.B) {
+	x := benchsample(10000)
+	buf := make([]float64, len(x))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Summary5Buf(x, buf)
+	}
+}
+
+func BenchmarkSummary5InPlace(b *testing.B) {
+	x := benchsample(10000)
+	y := make([]float64, len(x))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		copy(y, x)
+		b.StartTimer()
+		Summary5InPlace(y)
+	}
+}
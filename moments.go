@@ -0,0 +1,197 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+import (
+	"math"
+)
+
+// Moments -- represents a streaming accumulator of the weighted mean and
+// the central moments of orders 2, 3 and 4. The zero value has no observations.
+// The mean and the central sums are kept in compensated accumulators and
+// updated with the pairwise formulas of Pébay, so that accumulators of
+// disjoint parts of a sample (e.g. computed in parallel) can be merged.
+// The weights are frequency weights.
+//
+// Reference: Pébay, Formulas for Robust, One-Pass Parallel Computation of
+// Covariances and Arbitrary-Order Statistical Moments, Sandia Report
+// SAND2008-6212 (2008).
+//
+// DOI: https://doi.org/10.2172/1028931
+type Moments struct {
+	n          int64
+	w          float64
+	mean       Accu
+	m2, m3, m4 Accu
+}
+
+// Add -- adds the observation `x` with the unit weight.
+func (m *Moments) Add(x float64) {
+	m.AddW(x, 1)
+}
+
+// AddW -- adds the observation `x` with the weight `w`.
+// Panics if `w` is negative or not finite. Zero weights are counted
+// as observations but do not change the moments.
+func (m *Moments) AddW(x, w float64) {
+	if !(w >= 0) || math.IsInf(w, 0) {
+		panic("mym.Moments.AddW: invalid weight")
+	}
+	m.Merge(Moments{n: 1, w: w, mean: Accu{x, 0}})
+}
+
+// Merge -- adds the observations accumulated in `b`.
+func (m *Moments) Merge(b Moments) {
+	switch {
+	case b.w == 0:
+		m.n += b.n
+		return
+	case m.w == 0:
+		b.n += m.n
+		*m = b
+		return
+	}
+	na, nb := m.w, b.w
+	n := na + nb
+	δ := (b.mean.p - m.mean.p) + (b.mean.s - m.mean.s)
+	δn := δ / n
+	m2a, m2b := m.m2.Value(), b.m2.Value()
+	m3a, m3b := m.m3.Value(), b.m3.Value()
+	t := δ * δn * na * nb // δ²·na·nb/n
+	//
+	m.m4.Merge(b.m4)
+	m.m4.Add(t * δn * δn * (na*na - na*nb + nb*nb))
+	m.m4.Add(6 * δn * δn * (na*na*m2b + nb*nb*m2a))
+	m.m4.Add(4 * δn * (na*m3b - nb*m3a))
+	m.m3.Merge(b.m3)
+	m.m3.Add(t * δn * (na - nb))
+	m.m3.Add(3 * δn * (na*m2b - nb*m2a))
+	m.m2.Merge(b.m2)
+	m.m2.Add(t)
+	m.mean.Add(δn * nb)
+	m.n += b.n
+	m.w = n
+}
+
+// Count -- returns the number of observations.
+func (m Moments) Count() int64 {
+	return m.n
+}
+
+// W -- returns the sum of weights.
+func (m Moments) W() float64 {
+	return m.w
+}
+
+// Mean -- returns the weighted mean. Returns NaN if W()=0.
+func (m Moments) Mean() float64 {
+	if m.w == 0 {
+		return math.NaN()
+	}
+	return m.mean.Value()
+}
+
+// Var -- returns the unbiased variance Σw(x-mean)²/(W-1).
+// Returns NaN if W()≤1.
+func (m Moments) Var() float64 {
+	if !(m.w > 1) {
+		return math.NaN()
+	}
+	return math.Max(0, m.m2.Value()) / (m.w - 1)
+}
+
+// Std -- returns the square root of Var().
+func (m Moments) Std() float64 {
+	return math.Sqrt(m.Var())
+}
+
+// Skew -- returns the skewness g1 = m3/m2^(3/2), where mk are the central moments.
+// Returns NaN if W()=0 or all observations are equal.
+func (m Moments) Skew() float64 {
+	m2 := m.m2.Value()
+	if m.w == 0 || !(m2 > 0) {
+		return math.NaN()
+	}
+	return math.Sqrt(m.w) * m.m3.Value() / (m2 * math.Sqrt(m2))
+}
+
+// Kurt -- returns the excess kurtosis g2 = m4/m2²-3, where mk are the central moments.
+// Returns NaN if W()=0 or all observations are equal.
+func (m Moments) Kurt() float64 {
+	m2 := m.m2.Value()
+	if m.w == 0 || !(m2 > 0) {
+		return math.NaN()
+	}
+	return m.w*m.m4.Value()/(m2*m2) - 3
+}
+
+// MomentsOf -- returns the moments of a sample `x` with weights `w`
+// (unit weights if `w` is nil) computed by a two-pass algorithm.
+// Panics if len(w)≠len(x) or the weights are negative or not finite.
+func MomentsOf(x, w []float64) Moments {
+	if w != nil && len(w) != len(x) {
+		panic("mym.MomentsOf: len(w)≠len(x)")
+	}
+	wt := func(i int) float64 { return 1 }
+	if w != nil {
+		wt = func(i int) float64 { return w[i] }
+	}
+	var m Moments
+	var W, sx Accu
+	for i, xi := range x {
+		wi := wt(i)
+		if !(wi >= 0) || math.IsInf(wi, 0) {
+			panic("mym.MomentsOf: invalid weight")
+		}
+		W.Add(wi)
+		sx.AddProduct(wi, xi)
+	}
+	m.n = int64(len(x))
+	m.w = W.Value()
+	if m.w == 0 {
+		return m
+	}
+	μ := sx.Value() / m.w
+	// the second pass corrects the mean by Σw(x-μ)/W
+	var s1 Accu
+	for i, xi := range x {
+		wi := wt(i)
+		d := xi - μ
+		s1.AddProduct(wi, d)
+		d2 := d * d
+		m.m2.AddProduct(wi, d2)
+		m.m3.AddProduct(wi, d2*d)
+		m.m4.AddProduct(wi, d2*d2)
+	}
+	c := s1.Value() / m.w
+	m.mean = Accu{μ, 0}
+	m.mean.Add(c)
+	// shift the central sums from μ to μ+c
+	m2, m3 := m.m2.Value(), m.m3.Value()
+	m.m4.Add(-4*c*m3 + 6*c*c*m2 - 3*c*c*c*c*m.w)
+	m.m3.Add(-3*c*m2 + 2*c*c*c*m.w)
+	m.m2.Add(-c * c * m.w)
+	return m
+}
+
+// MeanVar -- returns the mean and the unbiased variance of a sample `x`
+// computed by the corrected two-pass algorithm with compensated sums.
+// The mean is NaN if len(x)=0, and the variance is NaN if len(x)<2.
+func MeanVar(x []float64) (mean, vari float64) {
+	m := MomentsOf(x, nil)
+	return m.Mean(), m.Var()
+}
+
+// MeanVarW -- returns the weighted mean and the unbiased variance Σw(x-mean)²/(W-1)
+// of a sample `x` with frequency weights `w`, W=Σw. The mean is NaN if W=0,
+// and the variance is NaN if W≤1. Panics if len(w)≠len(x) or the weights are
+// negative or not finite.
+func MeanVarW(x, w []float64) (mean, vari float64) {
+	if len(w) != len(x) {
+		panic("mym.MeanVarW: len(w)≠len(x)")
+	}
+	m := MomentsOf(x, w)
+	return m.Mean(), m.Var()
+}
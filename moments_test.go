@@ -0,0 +1,77 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMoments(t *testing.T) {
+	// exponential distribution shifted by 1e9:
+	// variance 1, skewness 2, excess kurtosis 6
+	const n = 200000
+	x := make([]float64, n)
+	w := make([]float64, n)
+	for i := range x {
+		x[i] = 1e9 - math.Log(U01())
+		w[i] = float64(1 + i%3)
+	}
+	//
+	batch := MomentsOf(x, nil)
+	var stream, part1, part2 Moments
+	for i, xi := range x {
+		stream.Add(xi)
+		if i < n/3 {
+			part1.Add(xi)
+		} else {
+			part2.Add(xi)
+		}
+	}
+	part1.Merge(part2)
+	for _, m := range []Moments{batch, stream, part1} {
+		if m.Count() != n || m.W() != n {
+			t.Fatalf("count=%v W=%v", m.Count(), m.W())
+		}
+		if math.Abs(m.Mean()-(1e9+1)) > 0.02 || math.Abs(m.Var()-1) > 0.05 ||
+			math.Abs(m.Skew()-2) > 0.2 || math.Abs(m.Kurt()-6) > 2 {
+			t.Fatalf("mean=%v var=%v skew=%v kurt=%v", m.Mean(), m.Var(), m.Skew(), m.Kurt())
+		}
+		if math.Abs(m.Mean()-batch.Mean()) > 1e-6 || math.Abs(m.Var()/batch.Var()-1) > 1e-9 ||
+			math.Abs(m.Skew()/batch.Skew()-1) > 1e-9 || math.Abs(m.Kurt()/batch.Kurt()-1) > 1e-9 {
+			t.Fatalf("stream: mean=%v var=%v skew=%v kurt=%v", m.Mean(), m.Var(), m.Skew(), m.Kurt())
+		}
+	}
+	// frequency weights are equivalent to repeated observations
+	var rep Moments
+	for i, xi := range x {
+		for k := 0; k < int(w[i]); k++ {
+			rep.Add(xi)
+		}
+	}
+	mw := MomentsOf(x, w)
+	var sw Moments
+	for i, xi := range x {
+		sw.AddW(xi, w[i])
+	}
+	for _, m := range []Moments{mw, sw} {
+		if m.W() != rep.W() || math.Abs(m.Mean()-rep.Mean()) > 1e-6 || math.Abs(m.Var()/rep.Var()-1) > 1e-9 ||
+			math.Abs(m.Skew()/rep.Skew()-1) > 1e-9 || math.Abs(m.Kurt()/rep.Kurt()-1) > 1e-9 {
+			t.Fatalf("weighted: mean=%v var=%v skew=%v kurt=%v", m.Mean(), m.Var(), m.Skew(), m.Kurt())
+		}
+	}
+	//
+	mean, vari := MeanVar([]float64{1e15 + 4, 1e15 + 7, 1e15 + 13, 1e15 + 16})
+	if mean != 1e15+10 || vari != 30 {
+		t.Fatalf("meanvar: %v %v", mean, vari)
+	}
+	mean, vari = MeanVarW([]float64{1, 2, 3}, []float64{1, 0, 3})
+	if mean != 2.5 || vari != 1 {
+		t.Fatalf("meanvarw: %v %v", mean, vari)
+	}
+	if m := MomentsOf(nil, nil); !math.IsNaN(m.Mean()) || !math.IsNaN(m.Var()) {
+		t.Fatalf("empty: %v %v", m.Mean(), m.Var())
+	}
+}
@@ -0,0 +1,320 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// Poly -- represents a Laurent polynomial Σc[k]x^k, where c[k] are the elements
+// of a vector `c` and the exponents k range over the index bounds of `c`
+// (negative exponents are allowed). The zero value is the zero polynomial.
+type Poly struct {
+	c RVec
+}
+
+// NewPoly -- returns the polynomial with the coefficients `c`.
+// The zero leading and trailing coefficients are discarded.
+func NewPoly(c RVec) Poly {
+	return Poly{c.Trim()}
+}
+
+// Coef -- returns the coefficients of `p`.
+func (p Poly) Coef() RVec {
+	return p.c.Copy()
+}
+
+// Ix -- returns the lowest and highest exponents of the nonzero coefficients of `p`.
+// Returns (Maxdex,Mindex) for the zero polynomial.
+func (p Poly) Ix() (lo, hi int) {
+	return p.c.Ix()
+}
+
+// Eval -- evaluates p(x) using the compensated Horner scheme (see CompHorner).
+func (p Poly) Eval(x float64) float64 {
+	L, U := p.c.Ix()
+	if U < L {
+		return 0
+	}
+	if p.c.sparse {
+		var a Accu
+		for k, i := range p.c.six {
+			a.AddProduct(p.c.sval[k], math.Pow(x, float64(i)))
+		}
+		return a.Value()
+	}
+	s, _ := CompHorner(U-L+1, func(i int) float64 { return p.c.vec[i] }, x)
+	if L == 0 {
+		return s
+	}
+	return s * math.Pow(x, float64(L))
+}
+
+// Add -- returns p+q.
+func (p Poly) Add(q Poly) Poly {
+	return Poly{p.c.Add(q.c).Trim()}
+}
+
+// Sub -- returns p-q.
+func (p Poly) Sub(q Poly) Poly {
+	return Poly{p.c.Sub(q.c).Trim()}
+}
+
+// Scale -- returns s·p.
+func (p Poly) Scale(s float64) Poly {
+	return Poly{p.c.Mul(s).Trim()}
+}
+
+// Mul -- returns p·q. The coefficients are computed by the direct algorithm
+// with compensated sums (FFT-based convolution is not used, since its absolute
+// error would swamp the small coefficients).
+func (p Poly) Mul(q Poly) Poly {
+	return Poly{convexact(p.c, q.c).Trim()}
+}
+
+// DivMod -- divides `p` by `d`. Let p=x^a·P and d=x^b·D, where P and D are
+// ordinary polynomials with nonzero constant terms. Returns q=x^(a-b)·Q and
+// r=x^a·R, where P=Q·D+R and deg(R)<deg(D), so that p=q·d+r.
+// Panics if `d` is zero, or if `d` is not a monomial and deg(P)>2^24.
+func (p Poly) DivMod(d Poly) (q, r Poly) {
+	dL, dU := d.c.Ix()
+	if dU < dL {
+		panic("mym.Poly.DivMod: division by zero")
+	}
+	pL, pU := p.c.Ix()
+	if pU < pL {
+		return
+	}
+	m, n := pU-pL, dU-dL // deg(P), deg(D)
+	if m < n {
+		return Poly{}, Poly{p.c.Trim()}
+	}
+	if n == 0 {
+		return Poly{p.c.Shift(-dL).Div(d.c.E(dL)).Trim()}, Poly{}
+	}
+	if m+1 > sparsemax {
+		panic("mym.Poly.DivMod: degree too large")
+	}
+	R := make([]float64, m+1)
+	for i := range R {
+		R[i] = p.c.E(pL + i)
+	}
+	Q := Genrvec(pL-dL, pL-dL+m-n)
+	lead := d.c.E(dU)
+	for k := m - n; k >= 0; k-- {
+		t := R[k+n] / lead
+		Q.U(pL-dL+k, t)
+		for j := 0; j <= n; j++ {
+			R[k+j] -= t * d.c.E(dL+j)
+		}
+		R[k+n] = 0
+	}
+	Rv := Genrvec(pL, pL+n-1)
+	for i := 0; i < n; i++ {
+		Rv.U(pL+i, R[i])
+	}
+	return Poly{Q.Trim()}, Poly{Rv.Trim()}
+}
+
+// Deriv -- returns the derivative p'.
+func (p Poly) Deriv() Poly {
+	L, U := p.c.Ix()
+	if U < L {
+		return Poly{}
+	}
+	six, sval := p.c.nonzeros()
+	e := make([]int, 0, len(six))
+	c := make([]float64, 0, len(six))
+	for k, i := range six {
+		if i != 0 {
+			e, c = append(e, i-1), append(c, float64(i)*sval[k])
+		}
+	}
+	return polyterms(L-1, U-1, e, c)
+}
+
+// Integ -- returns the antiderivative of `p` with the zero constant term.
+// Panics if the coefficient of x⁻¹ is nonzero.
+func (p Poly) Integ() Poly {
+	if p.c.E(-1) != 0 {
+		panic("mym.Poly.Integ: the coefficient of x⁻¹ is nonzero")
+	}
+	L, U := p.c.Ix()
+	if U < L {
+		return Poly{}
+	}
+	six, sval := p.c.nonzeros()
+	e := make([]int, len(six))
+	c := make([]float64, len(six))
+	for k, i := range six {
+		e[k], c[k] = i+1, sval[k]/float64(i+1)
+	}
+	return polyterms(L+1, U+1, e, c)
+}
+
+// polyterms -- returns the polynomial Σc[k]x^e[k], where lo≤e[0]<e[1]<...≤hi.
+func polyterms(lo, hi int, e []int, c []float64) Poly {
+	z := Genrvec(lo, hi)
+	if z.sparse {
+		var err error
+		if z, err = sparseof(lo, hi, e, c); err != nil {
+			panic(err.Error())
+		}
+		return Poly{z.Trim()}
+	}
+	for k, i := range e {
+		z.vec[i-lo] = c[k]
+	}
+	return Poly{z.Trim()}
+}
+
+// Compose -- returns the composition p(q(x)).
+// Panics if `p` has negative exponents, or if `q` is not a monomial
+// and deg(p)>2^24.
+func (p Poly) Compose(q Poly) Poly {
+	L, U := p.c.Ix()
+	if U < L {
+		return Poly{}
+	}
+	if L < 0 {
+		panic("mym.Poly.Compose: negative exponent")
+	}
+	if qL, qU := q.c.Ix(); qL == qU {
+		// q=a·x^j, p(q(x))=Σc[k]·a^k·x^(jk)
+		a, j := q.c.E(qL), qL
+		if j != 0 && U > Maxdex/imax(j, -j) {
+			panic(erriib)
+		}
+		six, sval := p.c.nonzeros()
+		e := make([]int, len(six))
+		c := make([]float64, len(six))
+		for k, i := range six {
+			e[k], c[k] = j*i, sval[k]*math.Pow(a, float64(i))
+		}
+		if j == 0 {
+			var s Accu
+			for _, ck := range c {
+				s.Add(ck)
+			}
+			return constpoly(s.Value())
+		}
+		if j < 0 {
+			for k, l := 0, len(e)-1; k < l; k, l = k+1, l-1 {
+				e[k], e[l] = e[l], e[k]
+				c[k], c[l] = c[l], c[k]
+			}
+		}
+		return polyterms(imin(j*L, j*U), imax(j*L, j*U), e, c)
+	}
+	if U+1 > sparsemax {
+		panic("mym.Poly.Compose: degree too large")
+	}
+	// Horner's scheme
+	var s Poly
+	for k := U; k >= 0; k-- {
+		s = s.Mul(q)
+		if c := p.c.E(k); c != 0 {
+			s = s.Add(constpoly(c))
+		}
+	}
+	return s
+}
+
+// constpoly -- returns the constant polynomial `c`.
+func constpoly(c float64) Poly {
+	z := Genrvec(0, 0)
+	z.U(0, c)
+	return Poly{z.Trim()}
+}
+
+// Roots -- returns the complex roots of p=x^a·P, where P is an ordinary polynomial
+// with a nonzero constant term. The roots of P are computed by the Aberth–Ehrlich
+// method and are followed by `a` zero roots if a>0 (for a<0, x=0 is a pole and
+// is not reported). Returns nil if `p` is zero or P is constant and a≤0.
+// Panics if the number of roots exceeds 2^24.
+//
+// Reference: Bini, Numerical computation of polynomial zeros by means of Aberth's
+// method, Numerical Algorithms, vol 13 (2), pp 179-200 (1996).
+//
+// DOI: https://doi.org/10.1007/BF02207694
+func (p Poly) Roots() []complex128 {
+	L, U := p.c.Ix()
+	if U < L || (U == L && L <= 0) {
+		return nil
+	}
+	n := U - L
+	if n+imax(L, 0) > sparsemax {
+		panic("mym.Poly.Roots: degree too large")
+	}
+	var z []complex128
+	if n > 0 {
+		a := make([]float64, n+1)
+		for i := range a {
+			a[i] = p.c.E(L + i)
+		}
+		z = aberth(a)
+	}
+	for k := 0; k < L; k++ {
+		z = append(z, 0)
+	}
+	return z
+}
+
+// aberth -- returns the roots of a[0]+a[1]x+...+a[n]x^n, a[0]≠0, a[n]≠0.
+func aberth(a []float64) []complex128 {
+	n := len(a) - 1
+	// the initial approximations lie on a circle with the radius
+	// equal to the geometric mean of the roots
+	r := math.Pow(math.Abs(a[0]/a[n]), 1/float64(n))
+	z := make([]complex128, n)
+	for k := range z {
+		θ := 2*math.Pi*float64(k)/float64(n) + 0.4
+		z[k] = complex(r*math.Cos(θ), r*math.Sin(θ))
+	}
+	eval := func(x complex128) (p, dp complex128) {
+		p = complex(a[n], 0)
+		for i := n - 1; i >= 0; i-- {
+			dp = dp*x + p
+			p = p*x + complex(a[i], 0)
+		}
+		return
+	}
+	done := make([]bool, n)
+	for iter := 0; iter < 500; iter++ {
+		conv := true
+		for i := range z {
+			if done[i] {
+				continue
+			}
+			p, dp := eval(z[i])
+			if p == 0 {
+				done[i] = true
+				continue
+			}
+			ratio := p / dp
+			var s complex128
+			for j := range z {
+				if j != i {
+					s += 1 / (z[i] - z[j])
+				}
+			}
+			w := ratio / (1 - ratio*s)
+			if cmplx.IsNaN(w) || cmplx.IsInf(w) {
+				w = 0
+			}
+			z[i] -= w
+			if cmplx.Abs(w) <= 2*Epsilon*cmplx.Abs(z[i]) {
+				done[i] = true
+			} else {
+				conv = false
+			}
+		}
+		if conv {
+			break
+		}
+	}
+	return z
+}
@@ -0,0 +1,208 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+// polyof -- returns the polynomial c[0]x^lwb+c[1]x^(lwb+1)+...
+func polyof(lwb int, c ...float64) Poly {
+	v := Genrvec(lwb, lwb+len(c)-1)
+	for i, ci := range c {
+		v.U(lwb+i, ci)
+	}
+	return NewPoly(v)
+}
+
+func polyeq(p, q Poly, tol float64) bool {
+	d := p.Sub(q).c
+	return d.Maxabs() <= tol
+}
+
+func TestPoly(t *testing.T) {
+	// p = 2x⁻² - 3 + x³
+	p := polyof(-2, 2, 0, -3, 0, 0, 1)
+	if L, U := p.Ix(); L != -2 || U != 3 {
+		t.Fatalf("p.Ix()=%v,%v", L, U)
+	}
+	for _, x := range []float64{-2, -0.5, 0.25, 1, 3} {
+		want := 2/(x*x) - 3 + x*x*x
+		if got := p.Eval(x); math.Abs(got-want) > 1e-14*math.Abs(want) {
+			t.Fatalf("p(%v)=%v, want %v", x, got, want)
+		}
+	}
+	// (x-1)(x+1) = x²-1, with offsets
+	q := polyof(-1, -1, 1).Mul(polyof(0, 1, 1))
+	if !polyeq(q, polyof(-1, -1, 0, 1), 0) {
+		t.Fatalf("mul: %v", q.c)
+	}
+	// p' = -4x⁻³ + 3x², ∫p' = p + 3
+	dp := p.Deriv()
+	if !polyeq(dp, polyof(-3, -4, 0, 0, 0, 0, 3), 0) || !polyeq(dp.Integ(), p.Add(polyof(0, 3)), 1e-15) {
+		t.Fatalf("deriv: %v", dp.c)
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("integ: no panic for x⁻¹")
+			}
+		}()
+		polyof(-1, 1).Integ()
+	}()
+	// p = q·d + r
+	d := polyof(-1, 1, 0, 1)
+	quo, rem := p.DivMod(d)
+	if !polyeq(quo.Mul(d).Add(rem), p, 1e-14) {
+		t.Fatalf("divmod: q=%v r=%v", quo.c, rem.c)
+	}
+	// (1+x)∘(x²-x⁻¹) = 1 + x² - x⁻¹
+	if c := polyof(0, 1, 1).Compose(polyof(-1, -1, 0, 0, 1)); !polyeq(c, polyof(-1, -1, 1, 0, 1), 0) {
+		t.Fatalf("compose: %v", c.c)
+	}
+	// (x²+1)∘(x+1) = x²+2x+2
+	if c := polyof(0, 1, 0, 1).Compose(polyof(0, 1, 1)); !polyeq(c, polyof(0, 2, 2, 1), 0) {
+		t.Fatalf("compose: %v", c.c)
+	}
+}
+
+func TestPolyRoots(t *testing.T) {
+	// x²·(x-1)(x-2)(x-3)(x²+1)
+	p := polyof(2, 1)
+	for _, r := range []float64{1, 2, 3} {
+		p = p.Mul(polyof(0, -r, 1))
+	}
+	p = p.Mul(polyof(0, 1, 0, 1))
+	z := p.Roots()
+	want := []complex128{0, 0, -1i, 1i, 1, 2, 3}
+	if len(z) != len(want) {
+		t.Fatalf("roots: %v", z)
+	}
+	used := make([]bool, len(z))
+	for _, w := range want {
+		found := false
+		for i := range z {
+			if !used[i] && cmplx.Abs(z[i]-w) <= 1e-12 {
+				used[i], found = true, true
+				break
+			}
+		}
+		if !found {
+			t.Fatalf("roots: %v, missing %v", z, w)
+		}
+	}
+	// Wilkinson-like polynomial of degree 10
+	w := polyof(0, 1)
+	for r := 1; r <= 10; r++ {
+		w = w.Mul(polyof(0, -float64(r), 1))
+	}
+	for _, zi := range w.Roots() {
+		r := math.Round(real(zi))
+		if cmplx.Abs(zi-complex(r, 0)) > 1e-8 {
+			t.Fatalf("wilkinson: %v", zi)
+		}
+	}
+	// monomials and constants
+	if z := polyof(3, 2).Roots(); len(z) != 3 || z[0] != 0 || z[1] != 0 || z[2] != 0 {
+		t.Fatalf("roots(2x³): %v", z)
+	}
+	for _, q := range []Poly{{}, polyof(0, 2), polyof(-2, 2)} {
+		if z := q.Roots(); z != nil {
+			t.Fatalf("roots(%v): %v", q.c, z)
+		}
+	}
+}
+
+func TestPolyMulLong(t *testing.T) {
+	// (1+x)^70·(1-x)^70 = (1-x²)^70
+	p, q := polyof(0, 1), polyof(0, 1)
+	for k := 0; k < 70; k++ {
+		p = p.Mul(polyof(0, 1, 1))
+		q = q.Mul(polyof(0, 1, -1))
+	}
+	r := p.Mul(q)
+	if L, U := r.Ix(); L != 0 || U != 140 {
+		t.Fatalf("Ix()=%v,%v", L, U)
+	}
+	if r.c.E(0) != 1 || r.c.E(2) != -70 || r.c.E(138) != -70 || r.c.E(140) != 1 {
+		t.Fatalf("c[0],c[2],c[138],c[140]=%v,%v,%v,%v", r.c.E(0), r.c.E(2), r.c.E(138), r.c.E(140))
+	}
+	// (1+x+...+x^99)·(1-x) = 1-x^100 with the exact zeros
+	c100 := make([]float64, 100)
+	for k := range c100 {
+		c100[k] = 1
+	}
+	r = NewPoly(RVecOf(0, c100)).Mul(polyof(0, 1, -1))
+	for k := 0; k <= 100; k++ {
+		want := 0.0
+		switch k {
+		case 0:
+			want = 1
+		case 100:
+			want = -1
+		}
+		if got := r.c.E(k); got != want {
+			t.Fatalf("c[%d]=%v, want %v", k, got, want)
+		}
+	}
+}
+
+func TestPolySparse(t *testing.T) {
+	const N = 1e12
+	terms := func(e []int, c []float64) Poly {
+		var v RVec
+		for k, i := range e {
+			v.U(i, c[k])
+		}
+		return NewPoly(v)
+	}
+	// p = x^N + 1
+	p := terms([]int{0, N}, []float64{1, 1})
+	if !p.c.IsSparse() {
+		t.Fatal("p is not sparse")
+	}
+	if dp := p.Deriv(); !polyeq(dp, terms([]int{N - 1}, []float64{N}), 0) {
+		t.Fatalf("deriv: %v", dp.c)
+	}
+	if ip := p.Integ(); !polyeq(ip, terms([]int{1, N + 1}, []float64{1, 1 / (N + 1)}), 0) {
+		t.Fatalf("integ: %v", ip.c)
+	}
+	// division by a monomial
+	q, r := p.DivMod(polyof(2, 2))
+	if !polyeq(q, terms([]int{-2, N - 2}, []float64{0.5, 0.5}), 0) || r.c.Norm1() != 0 {
+		t.Fatalf("divmod: q=%v r=%v", q.c, r.c)
+	}
+	if q, r := polyof(0, 1, 1).DivMod(p); q.c.Norm1() != 0 || !polyeq(r, polyof(0, 1, 1), 0) {
+		t.Fatalf("divmod: q=%v r=%v", q.c, r.c)
+	}
+	// composition with monomials
+	if c := p.Compose(polyof(2, 1)); !polyeq(c, terms([]int{0, 2 * N}, []float64{1, 1}), 0) {
+		t.Fatalf("compose: %v", c.c)
+	}
+	if c := p.Compose(polyof(-1, -1)); !polyeq(c, terms([]int{-N, 0}, []float64{1, 1}), 0) {
+		t.Fatalf("compose: %v", c.c)
+	}
+	if c := p.Compose(polyof(0, -1)); !polyeq(c, polyof(0, 2), 0) {
+		t.Fatalf("compose: %v", c.c)
+	}
+	for _, f := range []func(){
+		func() { p.DivMod(polyof(0, 1, 1)) },
+		func() { p.Compose(polyof(0, 1, 1)) },
+		func() { p.Compose(polyof(Maxdex/2, 1)) },
+		func() { p.Roots() },
+		func() { polyof(N, 1, 1).Roots() },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("no panic")
+				}
+			}()
+			f()
+		}()
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+import (
+	"math"
+	"math/big"
+)
+
+// nrepro -- the number of 32-bit bins of ReproAccu; the bins cover the bit positions
+// of all finite float64 numbers, 2^(-1074)...2^1023, and the carries.
+const nrepro = 68
+
+// ReproAccu -- represents a reproducible accumulator of sums. The terms are
+// accumulated exactly in integer bins aligned to fixed binary exponents, so the
+// accumulated sum does not depend on the order of the terms or on how they are
+// partitioned between accumulators merged with Merge. The result is the exact sum
+// correctly rounded to float64. The zero value is an empty sum. This is the limiting
+// case of the binned summation by Demmel and Nguyen, with the bins wide enough
+// to hold the exact sum.
+//
+// Reference: Demmel, Nguyen, Parallel Reproducible Summation, IEEE Transactions
+// on Computers, vol 64 (7), pp 2060-2070 (2015).
+//
+// DOI: https://doi.org/10.1109/TC.2014.2345391
+type ReproAccu struct {
+	bin  [nrepro]int64
+	nadd int
+	// non-finite terms
+	nan, pinf, ninf bool
+}
+
+// Add -- adds the term `x` to the accumulator.
+func (a *ReproAccu) Add(x float64) {
+	switch {
+	case x == 0:
+		return
+	case math.IsNaN(x):
+		a.nan = true
+		return
+	case math.IsInf(x, +1):
+		a.pinf = true
+		return
+	case math.IsInf(x, -1):
+		a.ninf = true
+		return
+	}
+	// x = ±m·2^(e-1075), e≥1
+	b := math.Float64bits(x)
+	e := int(b>>52) & 0x7ff
+	m := b & (1<<52 - 1)
+	if e == 0 {
+		e = 1
+	} else {
+		m |= 1 << 52
+	}
+	k, off := (e-1)/32, uint(e-1)%32
+	v0 := int64((m << off) & 0xffffffff)
+	v1 := int64((m >> (32 - off)) & 0xffffffff)
+	v2 := int64(m >> (64 - off))
+	if x < 0 {
+		a.bin[k] -= v0
+		a.bin[k+1] -= v1
+		a.bin[k+2] -= v2
+	} else {
+		a.bin[k] += v0
+		a.bin[k+1] += v1
+		a.bin[k+2] += v2
+	}
+	// each bin changes by less than 2^32 per term
+	if a.nadd++; a.nadd == 1<<30 {
+		a.carry()
+	}
+}
+
+// carry -- propagates the carries so that all but the last bin are in [0,2^32).
+func (a *ReproAccu) carry() {
+	for k := 0; k < nrepro-1; k++ {
+		c := a.bin[k] >> 32
+		a.bin[k] -= c << 32
+		a.bin[k+1] += c
+	}
+	a.nadd = 0
+}
+
+// Merge -- adds the partial sum accumulated in `b` to the accumulator.
+func (a *ReproAccu) Merge(b ReproAccu) {
+	a.carry()
+	b.carry()
+	for k := range a.bin {
+		a.bin[k] += b.bin[k]
+	}
+	a.nadd = 2
+	a.nan = a.nan || b.nan
+	a.pinf = a.pinf || b.pinf
+	a.ninf = a.ninf || b.ninf
+}
+
+// Value -- returns the accumulated sum correctly rounded to float64.
+// Returns NaN if a NaN or both +Inf and -Inf were added, and ±Inf if ±Inf was added.
+func (a ReproAccu) Value() float64 {
+	switch {
+	case a.nan || (a.pinf && a.ninf):
+		return math.NaN()
+	case a.pinf:
+		return math.Inf(+1)
+	case a.ninf:
+		return math.Inf(-1)
+	}
+	a.carry()
+	z := new(big.Int)
+	for k := nrepro - 1; k >= 0; k-- {
+		z.Lsh(z, 32)
+		z.Add(z, big.NewInt(a.bin[k]))
+	}
+	f := new(big.Float).SetInt(z)
+	f.SetMantExp(f, -1074)
+	x, _ := f.Float64()
+	return x
+}
+
+// ReproSum -- computes the sum f(0)+f(1)+...+f(n-1) correctly rounded to float64
+// using ReproAccu. The result is bit-identical for any order of the terms.
+func ReproSum(n int, f func(int) float64) float64 {
+	var a ReproAccu
+	for i := 0; i < n; i++ {
+		a.Add(f(i))
+	}
+	return a.Value()
+}
@@ -0,0 +1,254 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+import (
+	"math"
+	"sort"
+)
+
+// Spearman -- computes Spearman's rank correlation coefficient of `x` and `y`.
+// Tied elements receive the average rank. Returns NaN if len(x)<2.
+func Spearman(x, y []float64) float64 {
+	if len(x) != len(y) {
+		panic("mym.Spearman: len(x) != len(y)")
+	}
+	if len(x) < 2 {
+		return math.NaN()
+	}
+	rx, _ := midranks(x)
+	ry, _ := midranks(y)
+	return pearson(rx, ry)
+}
+
+// pearson -- computes Pearson's correlation coefficient of `x` and `y`.
+func pearson(x, y []float64) float64 {
+	n := len(x)
+	mx := AccuSum(n, func(i int) float64 { return x[i] }) / float64(n)
+	my := AccuSum(n, func(i int) float64 { return y[i] }) / float64(n)
+	sxy := AccuDot(n, func(i int) float64 { return x[i] - mx }, func(i int) float64 { return y[i] - my })
+	sxx := AccuDot(n, func(i int) float64 { return x[i] - mx }, func(i int) float64 { return x[i] - mx })
+	syy := AccuDot(n, func(i int) float64 { return y[i] - my }, func(i int) float64 { return y[i] - my })
+	return sxy / math.Sqrt(sxx*syy)
+}
+
+// KendallTauB -- computes Kendall's τ-b rank correlation coefficient
+// of `x` and `y` in O(n log n) time. Returns NaN if len(x)<2.
+//
+// Reference: Knight, A Computer Method for Calculating Kendall's Tau with
+// Ungrouped Data, Journal of the American Statistical Association,
+// vol 61 (314), pp 436-439 (1966).
+//
+// DOI: https://doi.org/10.1080/01621459.1966.10480879
+func KendallTauB(x, y []float64) float64 {
+	if len(x) != len(y) {
+		panic("mym.KendallTauB: len(x) != len(y)")
+	}
+	n := len(x)
+	if n < 2 {
+		return math.NaN()
+	}
+	//
+	ix := make([]int, n)
+	for i := range ix {
+		ix[i] = i
+	}
+	sort.Slice(ix, func(i, j int) bool {
+		a, b := ix[i], ix[j]
+		if f64EQ(x[a], x[b]) {
+			return f64LT(y[a], y[b])
+		}
+		return f64LT(x[a], x[b])
+	})
+	// n1 -- pairs tied in x, n3 -- pairs tied in both x and y
+	var n1, n3 int64
+	for i := 0; i < n; {
+		j := i + 1
+		for j < n && f64EQ(x[ix[j]], x[ix[i]]) {
+			j++
+		}
+		n1 += int64(j-i) * int64(j-i-1) / 2
+		for k := i; k < j; {
+			l := k + 1
+			for l < j && f64EQ(y[ix[l]], y[ix[k]]) {
+				l++
+			}
+			n3 += int64(l-k) * int64(l-k-1) / 2
+			k = l
+		}
+		i = j
+	}
+	// sort by y counting the discordant pairs
+	swaps := mergeswaps(ix, make([]int, n), y)
+	// n2 -- pairs tied in y
+	var n2 int64
+	for i := 0; i < n; {
+		j := i + 1
+		for j < n && f64EQ(y[ix[j]], y[ix[i]]) {
+			j++
+		}
+		n2 += int64(j-i) * int64(j-i-1) / 2
+		i = j
+	}
+	//
+	n0 := int64(n) * int64(n-1) / 2
+	s := float64(n0 - n1 - n2 + n3 - 2*swaps)
+	return s / math.Sqrt(float64(n0-n1)*float64(n0-n2))
+}
+
+// mergeswaps -- sorts `ix` by y[ix[i]] using a stable merge sort and
+// returns the number of pairs that were out of order (y strictly greater).
+func mergeswaps(ix, tmp []int, y []float64) int64 {
+	n := len(ix)
+	if n < 2 {
+		return 0
+	}
+	m := n / 2
+	swaps := mergeswaps(ix[:m], tmp[:m], y) + mergeswaps(ix[m:], tmp[m:], y)
+	i, j, k := 0, m, 0
+	for i < m && j < n {
+		if f64LT(y[ix[j]], y[ix[i]]) {
+			tmp[k] = ix[j]
+			swaps += int64(m - i)
+			j++
+		} else {
+			tmp[k] = ix[i]
+			i++
+		}
+		k++
+	}
+	k += copy(tmp[k:], ix[i:m])
+	copy(tmp[k:], ix[j:])
+	copy(ix, tmp)
+	return swaps
+}
+
+// Bicor -- computes the biweight midcorrelation of `x` and `y`.
+// Returns NaN if len(x)<2 or the MAD of `x` or `y` is zero.
+//
+// Reference: Wilcox, Introduction to Robust Estimation and Hypothesis Testing,
+// 4th ed, section 9.3.8 (2017).
+func Bicor(x, y []float64) float64 {
+	if len(x) != len(y) {
+		panic("mym.Bicor: len(x) != len(y)")
+	}
+	n := len(x)
+	if n < 2 {
+		return math.NaN()
+	}
+	a := biweights(x)
+	b := biweights(y)
+	if a == nil || b == nil {
+		return math.NaN()
+	}
+	sab := AccuDotSlice(a, b)
+	saa := AccuDotSlice(a, a)
+	sbb := AccuDotSlice(b, b)
+	return sab / math.Sqrt(saa*sbb)
+}
+
+// biweights -- returns (x[i]-med)·(1-u[i]²)²·I(|u[i]|<1), u[i]=(x[i]-med)/(9·mad).
+// Returns nil if mad=0.
+func biweights(x []float64) []float64 {
+	a := make([]float64, len(x))
+	med, mad := MedianMADBuf(x, a)
+	if !(mad > 0) {
+		return nil
+	}
+	for i, xi := range x {
+		d := xi - med
+		u := d / (9 * mad)
+		if math.Abs(u) < 1 {
+			a[i] = d * Sq(1-u*u)
+		} else {
+			a[i] = 0
+		}
+	}
+	return a
+}
+
+// CovGK -- computes the Gnanadesikan–Kettenring robust covariance of `x` and `y`
+// using the τ-scales (see TauEstim and FactorTau):
+//
+//	cov(x,y) = σx·σy·(σ²(x/σx+y/σy) - σ²(x/σx-y/σy))/4.
+//
+// Returns zero if the scale of `x` or `y` is zero.
+//
+// Reference: Gnanadesikan, Kettenring, Robust Estimates, Residuals, and Outlier
+// Detection with Multiresponse Data, Biometrics, vol 28 (1), pp 81-124 (1972).
+//
+// DOI: https://doi.org/10.2307/2528963
+func CovGK(x, y []float64) float64 {
+	if len(x) != len(y) {
+		panic("mym.CovGK: len(x) != len(y)")
+	}
+	sx, sy := tauscale(x), tauscale(y)
+	if !(sx > 0 && sy > 0) {
+		return 0
+	}
+	u := make([]float64, len(x))
+	v := make([]float64, len(x))
+	for i := range x {
+		xi, yi := x[i]/sx, y[i]/sy
+		u[i], v[i] = xi+yi, xi-yi
+	}
+	return sx * sy * (Sq(tauscale(u)) - Sq(tauscale(v))) / 4
+}
+
+// tauscale -- the τ-scale of `x` consistent with the N(0,1) Gaussian distribution.
+func tauscale(x []float64) float64 {
+	_, σ := TauEstim(x)
+	return FactorTau * σ
+}
+
+// SpearmanSym -- computes the matrix of Spearman's rank correlation coefficients
+// of the samples xs[0],xs[1],...,xs[len(xs)-1]. The diagonal elements are 1,
+// or NaN for the samples with all elements tied. Returns an empty matrix if len(xs)=0.
+func SpearmanSym(xs [][]float64) Sym {
+	return pairsym(xs, cordiag(Spearman), Spearman)
+}
+
+// KendallTauBSym -- computes the matrix of Kendall's τ-b rank correlation
+// coefficients of the samples xs[0],xs[1],...,xs[len(xs)-1]. The diagonal
+// elements are 1, or NaN for the samples with all elements tied.
+// Returns an empty matrix if len(xs)=0.
+func KendallTauBSym(xs [][]float64) Sym {
+	return pairsym(xs, cordiag(KendallTauB), KendallTauB)
+}
+
+// BicorSym -- computes the matrix of biweight midcorrelations
+// of the samples xs[0],xs[1],...,xs[len(xs)-1]. The diagonal elements are 1,
+// or NaN for the samples with zero MAD. Returns an empty matrix if len(xs)=0.
+func BicorSym(xs [][]float64) Sym {
+	return pairsym(xs, cordiag(Bicor), Bicor)
+}
+
+// CovGKSym -- computes the Gnanadesikan–Kettenring covariance matrix
+// of the samples xs[0],xs[1],...,xs[len(xs)-1]. Returns an empty matrix if len(xs)=0.
+func CovGKSym(xs [][]float64) Sym {
+	return pairsym(xs, func(x []float64) float64 { return Sq(tauscale(x)) }, CovGK)
+}
+
+// cordiag -- returns the function d(x)=1, or NaN if the correlation f(x,x) is undefined.
+func cordiag(f func(x, y []float64) float64) func([]float64) float64 {
+	return func(x []float64) float64 {
+		if math.IsNaN(f(x, x)) {
+			return math.NaN()
+		}
+		return 1
+	}
+}
+
+// pairsym -- returns the symmetric matrix a[i,i]=d(xs[i]), a[i,j]=f(xs[i],xs[j]).
+func pairsym(xs [][]float64, d func([]float64) float64, f func(x, y []float64) float64) Sym {
+	a := NewSym(len(xs))
+	for i := range xs {
+		a.Set(i, i, d(xs[i]))
+		for j := i + 1; j < len(xs); j++ {
+			a.Set(i, j, f(xs[i], xs[j]))
+		}
+	}
+	return a
+}
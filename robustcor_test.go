@@ -0,0 +1,164 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+import (
+	"math"
+	"testing"
+)
+
+// The reference values agree with R's cor(x,y,method="spearman"),
+// cor(x,y,method="kendall") and WGCNA::bicor(x,y).
+var robustcorX = []float64{2.1, 3.4, 1.9, 5.6, 4.4, 3.4, 7.8, 2.1, 6.0, 30.0}
+var robustcorY = []float64{1.0, 2.5, 2.5, 4.0, 3.1, 2.0, 6.2, 1.5, 5.1, -4.0}
+
+func TestSpearman(t *testing.T) {
+	for _, c := range []struct {
+		x, y []float64
+		want float64
+	}{
+		{robustcorX, robustcorY, 0.34556736511223823},
+		{[]float64{1, 2, 3, 4}, []float64{10, 20, 30, 40}, 1},
+		{[]float64{1, 2, 3, 4}, []float64{4, 3, 2, 1}, -1},
+		{[]float64{1, 2, 2, 3}, []float64{1, 2, 3, 3}, 5.0 / 6},
+		{[]float64{1, 2, 3}, []float64{5, 5, 5}, math.NaN()},
+		{[]float64{1}, []float64{1}, math.NaN()},
+	} {
+		if got := Spearman(c.x, c.y); !floateq(got, c.want, 1e-15) {
+			t.Fatalf("Spearman(%v,%v)=%v, want %v", c.x, c.y, got, c.want)
+		}
+	}
+}
+
+func TestKendallTauB(t *testing.T) {
+	for _, c := range []struct {
+		x, y []float64
+		want float64
+	}{
+		{robustcorX, robustcorY, 0.41382044088453257},
+		{[]float64{1, 2, 3, 4}, []float64{10, 20, 30, 40}, 1},
+		{[]float64{1, 2, 3, 4}, []float64{4, 3, 2, 1}, -1},
+		{[]float64{1, 2, 2, 3}, []float64{1, 2, 3, 3}, 0.8},
+		{[]float64{1, 1, 2, 2}, []float64{1, 2, 1, 2}, 0},
+		{[]float64{1, 2, 3}, []float64{5, 5, 5}, math.NaN()},
+		{[]float64{1}, []float64{1}, math.NaN()},
+	} {
+		if got := KendallTauB(c.x, c.y); !floateq(got, c.want, 1e-15) {
+			t.Fatalf("KendallTauB(%v,%v)=%v, want %v", c.x, c.y, got, c.want)
+		}
+	}
+	// the O(n log n) algorithm against the definition
+	x, y := make([]float64, 300), make([]float64, 300)
+	for i := range x {
+		x[i], y[i] = math.Floor(10*U01()), math.Floor(10*U01())+x[i]
+	}
+	var s, tx, ty float64
+	for i := range x {
+		for j := i + 1; j < len(x); j++ {
+			a, b := sign(x[i]-x[j]), sign(y[i]-y[j])
+			s += a * b
+			tx += a * a
+			ty += b * b
+		}
+	}
+	if got, want := KendallTauB(x, y), s/math.Sqrt(tx*ty); !floateq(got, want, 1e-13) {
+		t.Fatalf("KendallTauB=%v, want %v", got, want)
+	}
+}
+
+func TestBicor(t *testing.T) {
+	for _, c := range []struct {
+		x, y []float64
+		want float64
+	}{
+		{robustcorX, robustcorY, 0.76738251144708003},
+		{[]float64{1, 2, 3, 4}, []float64{10, 20, 30, 40}, 1},
+		{[]float64{1, 2, 3, 4}, []float64{4, 3, 2, 1}, -1},
+		{[]float64{1, 2, 3}, []float64{5, 5, 5}, math.NaN()},
+		{[]float64{1, 1, 1, 2}, []float64{1, 2, 3, 4}, math.NaN()}, // MAD=0
+	} {
+		if got := Bicor(c.x, c.y); !floateq(got, c.want, 1e-15) {
+			t.Fatalf("Bicor(%v,%v)=%v, want %v", c.x, c.y, got, c.want)
+		}
+	}
+}
+
+func TestCovGK(t *testing.T) {
+	const n = 20000
+	const ρ = 0.6
+	x, y := make([]float64, n), make([]float64, n)
+	for i := range x {
+		u, v := N01(), N01()
+		x[i], y[i] = 2*u, 3*(ρ*u+math.Sqrt(1-ρ*ρ)*v)
+	}
+	// a gross outlier does not break the estimate
+	x[0], y[0] = 1e6, -1e6
+	if got := CovGK(x, y); math.Abs(got-2*3*ρ) > 0.2 {
+		t.Fatalf("CovGK=%v, want %v", got, 2*3*ρ)
+	}
+	if got, want := CovGK(x, x), Sq(tauscale(x)); !floateq(got, want, 1e-12) {
+		t.Fatalf("CovGK(x,x)=%v, want %v", got, want)
+	}
+	if a, b := CovGK(x, y), CovGK(y, x); !floateq(a, b, 1e-12) {
+		t.Fatalf("CovGK(x,y)=%v, CovGK(y,x)=%v", a, b)
+	}
+	if got := CovGK([]float64{1, 2, 3}, []float64{5, 5, 5}); got != 0 {
+		t.Fatalf("CovGK(const)=%v", got)
+	}
+}
+
+func TestCorSym(t *testing.T) {
+	tied := []float64{3, 3, 3, 3, 3, 3, 3, 3, 3, 3}
+	xs := [][]float64{robustcorX, robustcorY, tied}
+	for _, c := range []struct {
+		name string
+		sym  func([][]float64) Sym
+		f    func(x, y []float64) float64
+		diag []float64
+	}{
+		{"SpearmanSym", SpearmanSym, Spearman, []float64{1, 1, math.NaN()}},
+		{"KendallTauBSym", KendallTauBSym, KendallTauB, []float64{1, 1, math.NaN()}},
+		{"BicorSym", BicorSym, Bicor, []float64{1, 1, math.NaN()}},
+		{"CovGKSym", CovGKSym, CovGK, []float64{Sq(tauscale(robustcorX)), Sq(tauscale(robustcorY)), 0}},
+	} {
+		a := c.sym(xs)
+		if a.N() != len(xs) {
+			t.Fatalf("%s: N()=%v", c.name, a.N())
+		}
+		for i := range xs {
+			for j := range xs {
+				want := c.diag[i]
+				if i != j {
+					want = c.f(xs[i], xs[j])
+				}
+				if got := a.Get(i, j); !floateq(got, want, 0) {
+					t.Fatalf("%s: a[%d,%d]=%v, want %v", c.name, i, j, got, want)
+				}
+			}
+		}
+		if e := c.sym(nil); e.N() != 0 {
+			t.Fatalf("%s(nil): N()=%v", c.name, e.N())
+		}
+	}
+}
+
+// floateq -- returns true iff |a-b|≤tol·max(1,|b|), or both `a` and `b` are NaN.
+func floateq(a, b, tol float64) bool {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return math.IsNaN(a) && math.IsNaN(b)
+	}
+	return math.Abs(a-b) <= tol*math.Max(1, math.Abs(b))
+}
+
+// sign -- returns the sign of `x`.
+func sign(x float64) float64 {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	}
+	return 0
+}
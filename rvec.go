@@ -9,9 +9,30 @@ import (
 )
 
 // RVec -- represents a vector of variable size with real (float64) elements.
+// The elements x[lwb],...,x[upb] are kept in a window of a backing buffer with
+// slack below and above, so that extending the index bounds element by element
+// takes amortized constant time. As with Go slices, a copy of a vector shares
+// the backing buffer with the original (the slack is reused only by the copy
+// whose elements adjoin it, so that copies never clobber each other). A vector with an index range longer
+// than 2^24 is kept in the sparse mode (see IsSparse).
 type RVec struct {
-	vec []float64
+	vec []float64 // x[lwb],...,x[upb], a subslice of buf
 	lwb int
+	buf []float64 // buf[k] stores x[bwb+k]
+	bwb int
+	bex *rvecbex // the indices of buf exposed by `x` and its copies
+	// the sparse mode
+	sparse bool
+	upb    int
+	six    []int     // the sorted indices of nonzero elements
+	sval   []float64 // the values of nonzero elements
+	snz    *int      // the length of six and sval claimed by `x` and its copies
+}
+
+// rvecbex -- the index range [lo,hi] of a buffer exposed by the vectors
+// that share the buffer; the buffer outside [lo,hi] is free slack.
+type rvecbex struct {
+	lo, hi int
 }
 
 const erriib = "invalid index bound"
@@ -40,6 +61,7 @@ const Maxdex = 1 << 48
 const Mindex = -Maxdex
 
 // Genrvec -- generates a new vector with the given lower and upper index bounds.
+// The vector is in the sparse mode if upb-lwb+1>2^24.
 func Genrvec(lwb, upb int) RVec {
 	if lwb < Mindex || lwb > Maxdex || upb < Mindex || upb > Maxdex {
 		panic(erriib)
@@ -47,11 +69,23 @@ func Genrvec(lwb, upb int) RVec {
 	if upb < lwb {
 		return RVec{}
 	}
-	return RVec{make([]float64, upb-lwb+1), lwb}
+	if upb-lwb+1 > sparsemax {
+		return RVec{sparse: true, lwb: lwb, upb: upb}
+	}
+	return denseof(lwb, make([]float64, upb-lwb+1))
+}
+
+// denseof -- returns the dense vector with the elements v[0],v[1],...
+// at the indices lwb,lwb+1,... The slice `v` is not copied.
+func denseof(lwb int, v []float64) RVec {
+	return RVec{vec: v, lwb: lwb, buf: v, bwb: lwb, bex: &rvecbex{lwb, lwb + len(v) - 1}}
 }
 
 // Ix -- returns the lower and upper index bounds of `x`.
 func (x RVec) Ix() (lwb, upb int) {
+	if x.sparse {
+		return x.lwb, x.upb
+	}
 	if len(x.vec) == 0 {
 		return Maxdex, Mindex
 	}
@@ -63,6 +97,9 @@ func (x RVec) E(i int) float64 {
 	if i < Mindex || i > Maxdex {
 		panic(errioob)
 	}
+	if x.sparse {
+		return x.spget(i)
+	}
 	L, U := x.Ix()
 	if i < L || i > U {
 		return 0
@@ -70,27 +107,120 @@ func (x RVec) E(i int) float64 {
 	return x.vec[i-L]
 }
 
-// U -- updates the element x[i]=e.
+// U -- updates the element x[i]=e. A dense vector switches to
+// the sparse mode if its index range becomes longer than 2^24.
 func (x *RVec) U(i int, e float64) {
 	if i < Mindex || i > Maxdex {
 		panic(errioob)
 	}
+	if x.sparse {
+		x.spset(i, e)
+		return
+	}
 	L, U := x.Ix()
 	//
 	if i < L || i > U {
 		newL, newU := imin(L, i), imax(U, i)
-		newvec := make([]float64, newU-newL+1)
-		for k := L; k <= U; k++ {
-			newvec[k-newL] = x.vec[k-L]
+		if newU-newL+1 > sparsemax {
+			*x = x.Sparse()
+			x.spset(i, e)
+			return
+		}
+		if !x.canexpose(newL, newU) {
+			// grow the buffer geometrically in the direction of the new index
+			// and keep the slack in the other direction
+			lo, hi := newL, newU
+			if len(x.buf) > 0 {
+				lo, hi = imin(lo, x.bwb), imax(hi, x.bwb+len(x.buf)-1)
+			}
+			extra := imax(len(x.vec), 4)
+			if i < L {
+				lo = imax(newL-extra, Mindex)
+			} else {
+				hi = imin(newU+extra, Maxdex)
+			}
+			x.realloc(lo, hi)
 		}
-		x.vec = newvec
-		x.lwb = newL
+		x.expose(newL, newU)
 		L = newL
 	}
 	//
 	x.vec[i-L] = e
 }
 
+// Reserve -- ensures that the index bounds of `x` can be extended to
+// [lwb,upb] without reallocation. The elements and Ix() are not changed.
+// Reserve has no effect if `x` is sparse or the resulting index range
+// would be longer than 2^24.
+func (x *RVec) Reserve(lwb, upb int) {
+	if lwb < Mindex || lwb > Maxdex || upb < Mindex || upb > Maxdex {
+		panic(erriib)
+	}
+	if upb < lwb || x.sparse {
+		return
+	}
+	L, U := x.Ix()
+	if len(x.vec) > 0 {
+		lwb, upb = imin(lwb, L), imax(upb, U)
+	}
+	if upb-lwb+1 > sparsemax {
+		return
+	}
+	if !x.canexpose(lwb, upb) {
+		x.realloc(lwb, upb)
+	}
+}
+
+// canexpose -- returns true iff the index bounds of `x` can be extended to
+// lwb...upb, which must include the index bounds of `x`, within its buffer:
+// the buffer must store the indices lwb...upb, and each extension must start
+// at the exposed frontier of the buffer, so that the elements exposed by
+// copies of `x` are not overwritten.
+func (x *RVec) canexpose(lwb, upb int) bool {
+	if x.bex == nil || lwb < x.bwb || upb >= x.bwb+len(x.buf) {
+		return false
+	}
+	if len(x.vec) == 0 {
+		return x.bex.hi < x.bex.lo
+	}
+	L, U := x.Ix()
+	return (lwb == L || L == x.bex.lo) && (upb == U || U == x.bex.hi)
+}
+
+// realloc -- moves the elements of `x` to a new buffer for the indices lwb...upb,
+// which must include the index bounds of `x`.
+func (x *RVec) realloc(lwb, upb int) {
+	buf := make([]float64, upb-lwb+1)
+	if len(x.vec) > 0 {
+		k := x.lwb - lwb
+		copy(buf[k:], x.vec)
+		x.vec = buf[k : k+len(x.vec)]
+	}
+	L, U := x.Ix()
+	x.buf, x.bwb, x.bex = buf, lwb, &rvecbex{L, U}
+}
+
+// expose -- extends the index bounds of `x` to lwb...upb, which must include
+// the index bounds of `x` and satisfy canexpose; new elements are zeroed.
+func (x *RVec) expose(lwb, upb int) {
+	x.bex.lo, x.bex.hi = imin(x.bex.lo, lwb), imax(x.bex.hi, upb)
+	L, U := x.Ix()
+	z := x.buf[lwb-x.bwb : upb-x.bwb+1]
+	if len(x.vec) == 0 {
+		for k := range z {
+			z[k] = 0
+		}
+	} else {
+		for k := lwb; k < L; k++ {
+			z[k-lwb] = 0
+		}
+		for k := U + 1; k <= upb; k++ {
+			z[k-lwb] = 0
+		}
+	}
+	x.vec, x.lwb = z, lwb
+}
+
 // meet -- returns the intersection of index bounds of two vectors.
 func (x RVec) meet(y RVec) (int, int) {
 	xL, xU := x.Ix()
@@ -107,106 +237,133 @@ func (x RVec) span(y RVec) (int, int) {
 
 // Copy -- returns a copy of `x`.
 func (x RVec) Copy() RVec {
+	if x.sparse {
+		return x.spmap(func(e float64) float64 { return e })
+	}
 	L, U := x.Ix()
 	z := Genrvec(L, U)
-	for i := L; i <= U; i++ {
-		z.U(i, x.E(i))
-	}
+	copy(z.vec, x.vec)
 	return z
 }
 
 // Neg -- returns the negation -x.
 func (x RVec) Neg() RVec {
+	if x.sparse {
+		return x.spmap(func(e float64) float64 { return -e })
+	}
 	L, U := x.Ix()
 	z := Genrvec(L, U)
-	for i := L; i <= U; i++ {
-		z.U(i, -x.E(i))
+	for i, e := range x.vec {
+		z.vec[i] = -e
 	}
 	return z
 }
 
 // Add -- returns the sum x+y.
 func (x RVec) Add(y RVec) RVec {
-	L, U := x.span(y)
-	z := Genrvec(L, U)
-	for i := L; i <= U; i++ {
-		z.U(i, x.E(i)+y.E(i))
-	}
-	return z
+	return x.Zip(y, func(a, b float64) float64 { return a + b })
 }
 
 // Sub -- returns the difference x-y.
 func (x RVec) Sub(y RVec) RVec {
-	L, U := x.span(y)
-	z := Genrvec(L, U)
-	for i := L; i <= U; i++ {
-		z.U(i, x.E(i)-y.E(i))
-	}
-	return z
+	return x.Zip(y, func(a, b float64) float64 { return a - b })
 }
 
 // Mul -- returns the product s*x.
 func (x RVec) Mul(s float64) RVec {
+	if x.sparse {
+		return x.spmap(func(e float64) float64 { return e * s })
+	}
 	L, U := x.Ix()
 	z := Genrvec(L, U)
-	for i := L; i <= U; i++ {
-		z.U(i, x.E(i)*s)
+	for i, e := range x.vec {
+		z.vec[i] = e * s
 	}
 	return z
 }
 
 // Div -- returns the product (1/s)*x.
 func (x RVec) Div(s float64) RVec {
+	if x.sparse {
+		return x.spmap(func(e float64) float64 { return e / s })
+	}
 	L, U := x.Ix()
 	z := Genrvec(L, U)
-	for i := L; i <= U; i++ {
-		z.U(i, x.E(i)/s)
+	for i, e := range x.vec {
+		z.vec[i] = e / s
 	}
 	return z
 }
 
-// Dot -- returns the scalar (dot) product of `x` and `y`.
+// Dot -- returns the scalar (dot) product of `x` and `y`
+// computed by a compensated algorithm (see AccuDot).
 func (x RVec) Dot(y RVec) float64 {
+	if x.sparse || y.sparse {
+		return x.spdot(y)
+	}
 	L, U := x.meet(y)
-	s := 0.0
-	for i := L; i <= U; i++ {
-		s += x.E(i) * y.E(i)
+	if U < L {
+		return 0
 	}
-	return s
+	return AccuDotSlice(x.vec[L-x.lwb:U-x.lwb+1], y.vec[L-y.lwb:U-y.lwb+1])
 }
 
-// Sum -- returns the sum of elements of `x`.
+// Sum -- returns the sum of elements of `x`
+// computed by a compensated algorithm (see AccuSum).
 func (x RVec) Sum() float64 {
-	L, U := x.Ix()
-	s := 0.0
-	for i := L; i <= U; i++ {
-		s += x.E(i)
+	if x.sparse {
+		return AccuSumSlice(x.sval)
 	}
-	return s
+	return AccuSumSlice(x.vec)
 }
 
-// Sumabs -- returns the sum of the absolute values of elements of `x`.
+// Sumabs -- returns the sum of the absolute values of elements of `x`
+// computed by a compensated algorithm (see AccuSum).
 func (x RVec) Sumabs() float64 {
-	L, U := x.Ix()
-	s := 0.0
-	for i := L; i <= U; i++ {
-		s += math.Abs(x.E(i))
+	v := x.vec
+	if x.sparse {
+		v = x.sval
 	}
-	return s
+	return AccuSum(len(v), func(i int) float64 { return math.Abs(v[i]) })
 }
 
-// Max -- returns the maximum of elements of `x`.
-func (x RVec) Max() float64 {
-	L, U := x.Ix()
-	s := 0.0
-	for i := L; i <= U; i++ {
-		s = math.Max(s, x.E(i))
+// Norm1 -- returns the L1 norm of `x` (same as Sumabs).
+func (x RVec) Norm1() float64 {
+	return x.Sumabs()
+}
+
+// Norm2 -- returns the Euclidean (L2) norm of `x` computed by
+// a compensated algorithm without overflow and underflow (see AccuNorm2).
+func (x RVec) Norm2() float64 {
+	v := x.vec
+	if x.sparse {
+		v = x.sval
 	}
+	return AccuNorm2(len(v), func(i int) float64 { return v[i] })
+}
+
+// NormInf -- returns the L∞ norm of `x` (same as Maxabs).
+func (x RVec) NormInf() float64 {
+	return x.Maxabs()
+}
+
+// Max -- returns the maximum of elements of `x` within the index bounds Ix().
+// NaN elements are ignored. Returns NaN if `x` is empty or all its elements are NaN.
+func (x RVec) Max() float64 {
+	_, s, _ := x.ArgMax()
 	return s
 }
 
 // Maxabs -- returns the maximum of the absolute values of elements of `x`.
+// Returns 0 if `x` is empty, and NaN if `x` contains NaNs.
 func (x RVec) Maxabs() float64 {
+	if x.sparse {
+		s := 0.0
+		for _, e := range x.sval {
+			s = math.Max(s, math.Abs(e))
+		}
+		return s
+	}
 	L, U := x.Ix()
 	s := 0.0
 	for i := L; i <= U; i++ {
@@ -215,12 +372,69 @@ func (x RVec) Maxabs() float64 {
 	return s
 }
 
-// Min -- returns the minimum of elements of `x`.
+// Min -- returns the minimum of elements of `x` within the index bounds Ix().
+// NaN elements are ignored. Returns NaN if `x` is empty or all its elements are NaN.
 func (x RVec) Min() float64 {
+	_, s, _ := x.ArgMin()
+	return s
+}
+
+// ArgMax -- returns the smallest index `i` such that x[i]=v is the maximum of elements
+// of `x` within the index bounds Ix(). NaN elements are ignored. Returns ok=false
+// (and v=NaN) if `x` is empty or all its elements are NaN.
+func (x RVec) ArgMax() (i int, v float64, ok bool) {
+	return x.argbest(func(a, b float64) bool { return a > b })
+}
+
+// ArgMin -- returns the smallest index `i` such that x[i]=v is the minimum of elements
+// of `x` within the index bounds Ix(). NaN elements are ignored. Returns ok=false
+// (and v=NaN) if `x` is empty or all its elements are NaN.
+func (x RVec) ArgMin() (i int, v float64, ok bool) {
+	return x.argbest(func(a, b float64) bool { return a < b })
+}
+
+// ArgMaxabs -- returns the smallest index `i` such that v=|x[i]| is the maximum of
+// the absolute values of elements of `x` within the index bounds Ix(). NaN elements
+// are ignored. Returns ok=false (and v=NaN) if `x` is empty or all its elements are NaN.
+func (x RVec) ArgMaxabs() (i int, v float64, ok bool) {
+	i, _, ok = x.argbest(func(a, b float64) bool { return math.Abs(a) > math.Abs(b) })
+	if ok {
+		v = math.Abs(x.E(i))
+	} else {
+		v = math.NaN()
+	}
+	return
+}
+
+// argbest -- returns the smallest index `i` of the non-NaN element x[i]=v
+// such that better(x[j],v) is false for all non-NaN elements x[j].
+func (x RVec) argbest(better func(a, b float64) bool) (i int, v float64, ok bool) {
+	if x.sparse {
+		v = math.NaN()
+		for k, e := range x.sval {
+			if math.IsNaN(e) {
+				continue
+			}
+			if !ok || better(e, v) {
+				i, v, ok = x.six[k], e, true
+			}
+		}
+		// the implicit zero with the smallest index
+		if g, gap := x.spgap(); gap && (!ok || better(0, v) || (!better(v, 0) && g < i)) {
+			i, v, ok = g, 0, true
+		}
+		return
+	}
 	L, U := x.Ix()
-	s := 0.0
-	for i := L; i <= U; i++ {
-		s = math.Min(s, x.E(i))
+	v = math.NaN()
+	for k := L; k <= U; k++ {
+		e := x.E(k)
+		if math.IsNaN(e) {
+			continue
+		}
+		if !ok || better(e, v) {
+			i, v, ok = k, e, true
+		}
 	}
-	return s
+	return
 }
@@ -0,0 +1,448 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestRVecMaxMin(t *testing.T) {
+	nan := math.NaN()
+	var empty RVec
+	if !math.IsNaN(empty.Max()) || !math.IsNaN(empty.Min()) {
+		t.Fatalf("empty: max=%v min=%v", empty.Max(), empty.Min())
+	}
+	if _, _, ok := empty.ArgMax(); ok {
+		t.Fatal("empty: argmax ok")
+	}
+	//
+	neg := Genrvec(-3, 1)
+	pos := Genrvec(5, 9)
+	for i := 0; i < 5; i++ {
+		neg.U(i-3, -float64(1+i%3))
+		pos.U(i+5, float64(1+i%3))
+	}
+	neg.U(0, nan)
+	// neg: -1 -2 -3 NaN -2, pos: 1 2 3 1 2
+	if neg.Max() != -1 || neg.Min() != -3 || pos.Max() != 3 || pos.Min() != 1 {
+		t.Fatalf("neg: %v %v, pos: %v %v", neg.Max(), neg.Min(), pos.Max(), pos.Min())
+	}
+	tests := []struct {
+		f    func() (int, float64, bool)
+		i    int
+		v    float64
+		want bool
+	}{
+		{neg.ArgMax, -3, -1, true},
+		{neg.ArgMin, -1, -3, true},
+		{neg.ArgMaxabs, -1, 3, true},
+		{pos.ArgMax, 7, 3, true},
+		{pos.ArgMin, 5, 1, true},
+		{pos.ArgMaxabs, 7, 3, true},
+	}
+	for k, tt := range tests {
+		i, v, ok := tt.f()
+		if i != tt.i || v != tt.v || ok != tt.want {
+			t.Fatalf("%d: got (%v,%v,%v), want (%v,%v,%v)", k, i, v, ok, tt.i, tt.v, tt.want)
+		}
+	}
+	//
+	allnan := Genrvec(0, 2)
+	for i := 0; i <= 2; i++ {
+		allnan.U(i, nan)
+	}
+	if _, v, ok := allnan.ArgMin(); ok || !math.IsNaN(v) || !math.IsNaN(allnan.Max()) {
+		t.Fatalf("allnan: %v %v", v, ok)
+	}
+}
+
+func TestRVecGrowth(t *testing.T) {
+	const n = 10000
+	var x RVec
+	for k := 0; k < n; k++ {
+		x.U(k, float64(k))
+		x.U(-k, float64(-k))
+	}
+	y := x // y shares the buffer but keeps its bounds
+	x.U(-n-5, 1)
+	x.U(n+5, 2)
+	y.U(n+3, 3)
+	if L, U := x.Ix(); L != -n-5 || U != n+5 {
+		t.Fatalf("x.Ix()=%v,%v", L, U)
+	}
+	for k := -n - 5; k <= n+5; k++ {
+		want := float64(k)
+		switch {
+		case k == -n-5:
+			want = 1
+		case k == n+5:
+			want = 2
+		case k <= -n || k >= n:
+			want = 0
+		}
+		if x.E(k) != want {
+			t.Fatalf("x[%d]=%v, want %v", k, x.E(k), want)
+		}
+	}
+	// y does not see the growth of x and vice versa
+	if L, U := y.Ix(); L != -n+1 || U != n+3 {
+		t.Fatalf("y.Ix()=%v,%v", L, U)
+	}
+	if y.E(n) != 0 || y.E(n+1) != 0 || y.E(n+2) != 0 || y.E(n+3) != 3 || y.E(n-1) != n-1 {
+		t.Fatalf("y[n-1...n+3]=%v,%v,%v,%v,%v", y.E(n-1), y.E(n), y.E(n+1), y.E(n+2), y.E(n+3))
+	}
+	y.U(-n-1, 4)
+	if x.E(-n-1) != 0 || y.E(-n-1) != 4 {
+		t.Fatalf("x[-n-1]=%v, y[-n-1]=%v", x.E(-n-1), y.E(-n-1))
+	}
+	// the first copy to grow reuses the slack, the other one reallocates
+	var u RVec
+	u.Reserve(0, 100)
+	u.U(0, 1)
+	v := u
+	v.U(1, 2)
+	u.U(1, 3)
+	if v.E(1) != 2 || u.E(1) != 3 {
+		t.Fatalf("v[1]=%v, u[1]=%v", v.E(1), u.E(1))
+	}
+	if &v.vec[0] == &u.vec[0] || len(v.buf) != 101 {
+		t.Fatal("u and v share the elements")
+	}
+	//
+	var z RVec
+	z.Reserve(-100, 100)
+	if L, U := z.Ix(); L <= U {
+		t.Fatalf("reserve changed bounds: %v,%v", L, U)
+	}
+	buf := z.buf
+	for k := 100; k >= -100; k-- {
+		z.U(k, 1)
+	}
+	if &z.buf[0] != &buf[0] || z.Sum() != 201 {
+		t.Fatal("reserve: reallocated")
+	}
+}
+
+func benchRVecAppend(b *testing.B, n int) {
+	b.ReportAllocs()
+	for it := 0; it < b.N; it++ {
+		var x RVec
+		for k := 0; k < n; k++ {
+			x.U(k, 1)
+			x.U(-k, 1)
+		}
+	}
+}
+
+func BenchmarkRVecAppend1e3(b *testing.B) { benchRVecAppend(b, 1000) }
+func BenchmarkRVecAppend1e4(b *testing.B) { benchRVecAppend(b, 10000) }
+func BenchmarkRVecAppend1e5(b *testing.B) { benchRVecAppend(b, 100000) }
+
+func TestRVecSparse(t *testing.T) {
+	var x RVec
+	x.U(-1e12, 2)
+	x.U(1e12, -3)
+	if !x.IsSparse() {
+		t.Fatal("x is not sparse")
+	}
+	if L, U := x.Ix(); L != -1e12 || U != 1e12 || x.E(-1e12) != 2 || x.E(0) != 0 || x.E(1e12) != -3 {
+		t.Fatalf("x: Ix()=%v,%v", L, U)
+	}
+	if x.Max() != 2 || x.Min() != -3 || x.Maxabs() != 3 || x.Sum() != -1 || x.Sumabs() != 5 {
+		t.Fatalf("x: max=%v min=%v maxabs=%v sum=%v sumabs=%v", x.Max(), x.Min(), x.Maxabs(), x.Sum(), x.Sumabs())
+	}
+	if i, v, ok := x.ArgMin(); i != 1e12 || v != -3 || !ok {
+		t.Fatalf("x: argmin=%v,%v,%v", i, v, ok)
+	}
+	if i, v, ok := x.Neg().ArgMax(); i != 1e12 || v != 3 || !ok {
+		t.Fatalf("-x: argmax=%v,%v,%v", i, v, ok)
+	}
+	if i, v, ok := x.Mul(-1).ArgMin(); i != -1e12 || v != -2 || !ok {
+		t.Fatalf("-x: argmin=%v,%v,%v", i, v, ok)
+	}
+	if i, v, ok := x.Mul(0).ArgMin(); i != -1e12 || v != 0 || !ok {
+		t.Fatalf("0x: argmin=%v,%v,%v", i, v, ok)
+	}
+	neg := x.Copy()
+	neg.U(-1e12, -2)
+	if i, v, ok := neg.ArgMax(); i != 1-1e12 || v != 0 || !ok || x.E(-1e12) != 2 {
+		t.Fatalf("neg: argmax=%v,%v,%v", i, v, ok)
+	}
+	//
+	y := Genrvec(-5, 5)
+	for i := -5; i <= 5; i++ {
+		y.U(i, float64(i))
+	}
+	y.U(1e12, 10)
+	if !y.IsSparse() || y.E(3) != 3 || y.E(-5) != -5 || y.E(1e12) != 10 {
+		t.Fatal("y: dense to sparse")
+	}
+	if d := x.Dot(y); d != -30 {
+		t.Fatalf("x·y=%v", d)
+	}
+	z := x.Add(y).Sub(x)
+	if L, U := z.Ix(); L != -1e12 || U != 1e12 || z.Sum() != 10 || z.Sparse().E(4) != 4 {
+		t.Fatalf("x+y-x: Ix()=%v,%v sum=%v", L, U, z.Sum())
+	}
+	z.U(1e12, 0)
+	z.U(-1e12, 0)
+	z.U(0, 7)
+	if z.Sum() != 7 || z.Copy().Dot(z) != 7*7+2*(1+4+9+16+25) {
+		t.Fatalf("z: sum=%v", z.Sum())
+	}
+	//
+	w := Genrvec(-3, 3).Add(Genrvec(1e9, 1e9))
+	if !w.IsSparse() {
+		t.Fatal("w is not sparse")
+	}
+	w.U(2, 5)
+	d := Genrvec(-3, 3).Sparse()
+	d.U(2, 5)
+	if d.Dense().E(2) != 5 || d.Dense().IsSparse() || d.Dense().Dot(w) != 25 {
+		t.Fatal("dense")
+	}
+}
+
+func TestRVecSparseCopy(t *testing.T) {
+	var x RVec
+	x.U(-1e12, 1)
+	x.U(5, 2)
+	x.U(1e12, 3)
+	y := x
+	x.U(0, 7) // insertion
+	x.U(5, 0) // deletion
+	x.U(-1e12, 0)
+	check := func(name string, z RVec, six []int, sval []float64) {
+		zi, zv := z.nonzeros()
+		if fmt.Sprint(zi, zv) != fmt.Sprint(six, sval) {
+			t.Fatalf("%s: six=%v sval=%v, want %v %v", name, zi, zv, six, sval)
+		}
+	}
+	check("y", y, []int{-1e12, 5, 1e12}, []float64{1, 2, 3})
+	check("x", x, []int{0, 1e12}, []float64{7, 3})
+}
+
+func TestRVecSparseAppend(t *testing.T) {
+	const n = 100000
+	var x RVec
+	x.U(-1e12, 1)
+	allocs := 0
+	for k := 1; k <= n; k++ {
+		p := x.six
+		x.U(k*1e6, float64(k))
+		if len(p) == 0 || &p[0] != &x.six[0] {
+			allocs++
+		}
+	}
+	if !x.IsSparse() || len(x.six) != n+1 || allocs > 40 {
+		t.Fatalf("nnz=%v, allocs=%v", len(x.six), allocs)
+	}
+	// the appends to copies do not interfere
+	y := x
+	x.U(2e12, 2)
+	y.U(3e12, 3)
+	if x.E(3e12) != 0 || y.E(2e12) != 0 || x.E(2e12) != 2 || y.E(3e12) != 3 {
+		t.Fatalf("x=%v,%v y=%v,%v", x.E(2e12), x.E(3e12), y.E(2e12), y.E(3e12))
+	}
+	// deletion of the last element keeps the copies intact
+	z := x
+	x.U(2e12, 0)
+	x.U(4e12, 4)
+	if z.E(2e12) != 2 || z.E(4e12) != 0 || x.E(2e12) != 0 || x.E(4e12) != 4 {
+		t.Fatalf("x=%v,%v z=%v,%v", x.E(2e12), x.E(4e12), z.E(2e12), z.E(4e12))
+	}
+	// the bulk producers build sparse vectors directly
+	v := make([]float64, sparsemax+1)
+	v[0], v[sparsemax] = 1, 2
+	if r := RVecOf(5, v); !r.IsSparse() || len(r.six) != 2 || r.E(5) != 1 || r.E(5+sparsemax) != 2 {
+		t.Fatalf("RVecOf: %v %v", r.six, r.sval)
+	}
+}
+
+func BenchmarkRVecSparseAppend(b *testing.B) {
+	b.ReportAllocs()
+	for it := 0; it < b.N; it++ {
+		var x RVec
+		x.U(-1e12, 1)
+		for k := 1; k <= 10000; k++ {
+			x.U(k*1e6, 1)
+		}
+	}
+}
+
+func TestRVecAccu(t *testing.T) {
+	// ill-conditioned sum and dot product
+	x := Genrvec(-2, 2)
+	y := Genrvec(0, 4)
+	for i, e := range []float64{1e20, 3, -1e20, 4, 1} {
+		x.U(i-2, e)
+		y.U(i, e)
+	}
+	if s := x.Sum(); s != 8 {
+		t.Fatalf("sum=%v", s)
+	}
+	// z·y = 1e10·1e10 + 1·3 + 1e10·(-1e10)
+	y.U(0, 1e10)
+	y.U(2, -1e10)
+	z := Genrvec(0, 2)
+	z.U(0, 1e10)
+	z.U(1, 1)
+	z.U(2, 1e10)
+	if d := z.Dot(y); d != 3 {
+		t.Fatalf("dot=%v", d)
+	}
+	if d := z.Sparse().Dot(y); d != 3 {
+		t.Fatalf("sparse dot=%v", d)
+	}
+	//
+	v := Genrvec(1, 2)
+	v.U(1, 3e300)
+	v.U(2, -4e300)
+	if v.Norm1() != 7e300 || v.Norm2() != 5e300 || v.NormInf() != 4e300 || v.Sparse().Norm2() != 5e300 {
+		t.Fatalf("norms: %v %v %v", v.Norm1(), v.Norm2(), v.NormInf())
+	}
+	var empty RVec
+	if empty.Norm1() != 0 || empty.Norm2() != 0 || empty.NormInf() != 0 || empty.Dot(v) != 0 {
+		t.Fatal("empty norms")
+	}
+}
+
+func TestRVecInPlace(t *testing.T) {
+	x := Genrvec(0, 4)
+	for i := 0; i <= 4; i++ {
+		x.U(i, float64(i))
+	}
+	y := Genrvec(-2, 1)
+	y.U(-2, 1)
+	y.U(1, 1)
+	//
+	z := x.Copy()
+	z.Axpy(2, y)
+	if L, U := z.Ix(); L != -2 || U != 4 || z.E(-2) != 2 || z.E(-1) != 0 || z.E(1) != 3 || z.Sum() != 14 {
+		t.Fatalf("axpy: %v,%v sum=%v", L, U, z.Sum())
+	}
+	z.AddTo(x.Neg())
+	z.Scale(0.5)
+	if L, U := z.Ix(); L != -2 || U != 4 || z.Sub(y).Maxabs() != 0 {
+		t.Fatal("addto/scale")
+	}
+	if m := x.Map(func(e float64) float64 { return e * e }); m.Sum() != 30 {
+		t.Fatalf("map: sum=%v", m.Sum())
+	}
+	if p := x.Zip(y, func(a, b float64) float64 { return a*b + 1 }); p.Sum() != 1+7 {
+		t.Fatalf("zip: sum=%v", p.Sum())
+	}
+	//
+	v := x.View(2, 10)
+	if L, U := v.Ix(); L != 2 || U != 4 {
+		t.Fatalf("view: Ix()=%v,%v", L, U)
+	}
+	v.Scale(-1)
+	v.U(3, 7)
+	if x.E(2) != -2 || x.E(3) != 7 || x.E(4) != -4 || x.E(1) != 1 {
+		t.Fatal("view: not shared")
+	}
+	v.U(5, 1) // detaches
+	v.U(2, 100)
+	if x.E(2) != -2 || x.E(5) != 0 {
+		t.Fatal("view: not detached")
+	}
+	//
+	var s RVec
+	s.U(-1e12, 1)
+	s.U(1e12, 2)
+	s.Axpy(3, x)
+	s.Scale(2)
+	if !s.IsSparse() || s.E(1e12) != 4 || s.E(3) != 42 || s.View(-5, 5).Sum() != 6*(0+1-2+7-4) {
+		t.Fatalf("sparse: %v %v %v", s.E(1e12), s.E(3), s.View(-5, 5).Sum())
+	}
+}
+
+func BenchmarkRVecAdd(b *testing.B) {
+	x := randrvec(0, 10000)
+	y := randrvec(-5000, 10000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for it := 0; it < b.N; it++ {
+		benchsink = x.Add(y).Sum()
+	}
+}
+
+func BenchmarkRVecAxpy(b *testing.B) {
+	x := randrvec(0, 10000)
+	y := randrvec(-5000, 10000)
+	x.Reserve(-5000, 10000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for it := 0; it < b.N; it++ {
+		x.Axpy(1e-9, y)
+	}
+}
+
+// seqvec -- a Vector with the elements 1,2,...,n.
+type seqvec int
+
+func (v seqvec) Len() int            { return int(v) }
+func (v seqvec) AtVec(i int) float64 { return float64(i + 1) }
+
+func TestRVecConv(t *testing.T) {
+	x := RVecOf(-2, []float64{0, 0, 1, 2, 0, 3, 0})
+	if L, U := x.Ix(); L != -2 || U != 4 {
+		t.Fatalf("rvecof: Ix()=%v,%v", L, U)
+	}
+	s, lwb := x.Float64s()
+	if lwb != -2 || len(s) != 7 || s[3] != 2 {
+		t.Fatalf("float64s: %v %v", s, lwb)
+	}
+	s[3] = 100
+	if x.E(1) != 2 {
+		t.Fatal("float64s: not a copy")
+	}
+	//
+	tr := x.Trim()
+	if !tr.Equal(RVecOf(0, []float64{1, 2, 0, 3})) || tr.Equal(x) || !tr.ApproxEqual(x, 0) {
+		t.Fatal("trim")
+	}
+	if !x.Slice(1, 6).Equal(RVecOf(1, []float64{2, 0, 3, 0, 0, 0})) {
+		t.Fatal("slice")
+	}
+	if !x.Shift(5).Equal(RVecOf(3, []float64{0, 0, 1, 2, 0, 3, 0})) {
+		t.Fatal("shift")
+	}
+	y := x.Copy()
+	y.U(3, 3+1e-12)
+	if y.Equal(x) || !y.ApproxEqual(x, 1e-12) || y.ApproxEqual(x, 1e-14) {
+		t.Fatal("approxequal")
+	}
+	//
+	var sp RVec
+	sp.U(-1e12, 0)
+	sp.U(-7, 1)
+	sp.U(9, 2)
+	sp.U(1e12, 0)
+	want := Genrvec(-7, 9)
+	want.U(-7, 1)
+	want.U(9, 2)
+	if tr := sp.Trim(); tr.IsSparse() || !tr.Equal(want) {
+		t.Fatal("sparse trim")
+	}
+	if sh := sp.Shift(-5); !sh.IsSparse() || sh.E(4) != 2 || sh.E(-12) != 1 {
+		t.Fatal("sparse shift")
+	}
+	if sl := sp.Slice(-1e10, 1e10); !sl.IsSparse() || sl.Sum() != 3 {
+		t.Fatal("sparse slice")
+	}
+	//
+	v := RVecFromVector(10, seqvec(4))
+	if !v.Equal(RVecOf(10, []float64{1, 2, 3, 4})) {
+		t.Fatal("rvecfromvector")
+	}
+	a := v.AsVector()
+	if a.Len() != 4 || a.AtVec(3) != 4 || !RVecFromVector(10, a).Equal(v) {
+		t.Fatal("asvector")
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+import (
+	"math"
+)
+
+// RVecOf -- returns the vector with the elements x[0],x[1],... at the indices
+// lwb,lwb+1,... The elements are copied.
+func RVecOf(lwb int, x []float64) RVec {
+	return rvecgen(lwb, len(x), func(i int) float64 { return x[i] })
+}
+
+// rvecgen -- returns the vector with the elements f(0),f(1),...,f(n-1)
+// at the indices lwb,lwb+1,...,lwb+n-1.
+func rvecgen(lwb, n int, f func(int) float64) RVec {
+	if n == 0 {
+		return RVec{}
+	}
+	z := Genrvec(lwb, lwb+n-1)
+	if !z.sparse {
+		for i := range z.vec {
+			z.vec[i] = f(i)
+		}
+		return z
+	}
+	for i := 0; i < n; i++ {
+		if e := f(i); e != 0 {
+			z.six = append(z.six, lwb+i)
+			z.sval = append(z.sval, e)
+		}
+	}
+	return z
+}
+
+// Float64s -- returns a copy of the elements of `x` as a slice `s`, where
+// s[i-lwb]=x[i] and lwb is the lower index bound of `x`.
+// Panics if the index range of `x` is longer than 2^24.
+func (x RVec) Float64s() (s []float64, lwb int) {
+	L, U := x.Ix()
+	if U < L {
+		return nil, 0
+	}
+	if x.sparse {
+		x = x.Dense()
+	}
+	s = make([]float64, len(x.vec))
+	copy(s, x.vec)
+	return s, L
+}
+
+// Trim -- returns a copy of `x` without the zero elements at both ends.
+func (x RVec) Trim() RVec {
+	if x.sparse {
+		if len(x.six) == 0 {
+			return RVec{}
+		}
+		return x.Slice(x.six[0], x.six[len(x.six)-1])
+	}
+	L, U := x.Ix()
+	for L <= U && x.E(L) == 0 {
+		L++
+	}
+	for L <= U && x.E(U) == 0 {
+		U--
+	}
+	return x.Slice(L, U)
+}
+
+// Slice -- returns a copy of the elements x[lo],...,x[hi] with the index bounds [lo,hi].
+// The elements outside the index bounds of `x` are zero. See also View.
+func (x RVec) Slice(lo, hi int) RVec {
+	z := Genrvec(lo, hi)
+	if hi < lo {
+		return z
+	}
+	if z.sparse {
+		xi, xv := x.nonzeros()
+		for k, i := range xi {
+			if lo <= i && i <= hi {
+				z.six = append(z.six, i)
+				z.sval = append(z.sval, xv[k])
+			}
+		}
+		return z
+	}
+	if x.sparse {
+		for k, i := range x.six {
+			if lo <= i && i <= hi {
+				z.vec[i-lo] = x.sval[k]
+			}
+		}
+		return z
+	}
+	L, U := x.Ix()
+	a, b := imax(lo, L), imin(hi, U)
+	if a <= b {
+		copy(z.vec[a-lo:], x.vec[a-L:b-L+1])
+	}
+	return z
+}
+
+// Shift -- returns the vector z[i+k]=x[i], that is, `x` with the index bounds
+// shifted by `k`. Panics if the shifted index bounds are invalid.
+func (x RVec) Shift(k int) RVec {
+	L, U := x.Ix()
+	if U < L {
+		return RVec{}
+	}
+	if L+k < Mindex || U+k > Maxdex {
+		panic(erriib)
+	}
+	z := x.Copy()
+	if !z.sparse {
+		return denseof(L+k, z.vec)
+	}
+	z.lwb += k
+	z.upb += k
+	for j := range z.six {
+		z.six[j] += k
+	}
+	return z
+}
+
+// Equal -- returns true iff `x` and `y` have the same index bounds and
+// the same elements (compared with ==, so NaN elements are never equal).
+func (x RVec) Equal(y RVec) bool {
+	xL, xU := x.Ix()
+	yL, yU := y.Ix()
+	if xL != yL || xU != yU {
+		return false
+	}
+	equal := true
+	x.Zip(y, func(a, b float64) float64 {
+		if a != b {
+			equal = false
+		}
+		return 0
+	})
+	return equal
+}
+
+// ApproxEqual -- returns true iff |x[i]-y[i]|≤tol·max(1,|x[i]|,|y[i]|) for
+// all indices `i`; the index bounds of `x` and `y` may differ (the elements
+// outside the bounds are zero). NaN elements are never approximately equal.
+func (x RVec) ApproxEqual(y RVec, tol float64) bool {
+	equal := true
+	x.Zip(y, func(a, b float64) float64 {
+		if !(math.Abs(a-b) <= tol*math.Max(1, math.Max(math.Abs(a), math.Abs(b)))) {
+			equal = false
+		}
+		return 0
+	})
+	return equal
+}
+
+// Vector -- represents a vector with the elements AtVec(0),...,AtVec(Len()-1).
+// The interface is a subset of gonum's mat.Vector, so gonum vectors can be
+// converted by RVecFromVector.
+type Vector interface {
+	Len() int
+	AtVec(i int) float64
+}
+
+// RVecFromVector -- returns the vector with the elements v.AtVec(0),v.AtVec(1),...
+// at the indices lwb,lwb+1,...
+func RVecFromVector(lwb int, v Vector) RVec {
+	return rvecgen(lwb, v.Len(), v.AtVec)
+}
+
+// AsVector -- returns a Vector that accesses the elements of `x` within
+// its index bounds [lwb,upb], so that AtVec(i)=x[lwb+i].
+func (x RVec) AsVector() Vector {
+	return rvecvector{x}
+}
+
+// rvecvector -- the Vector adapter of RVec.
+type rvecvector struct {
+	x RVec
+}
+
+func (v rvecvector) Len() int {
+	L, U := v.x.Ix()
+	if U < L {
+		return 0
+	}
+	return U - L + 1
+}
+
+func (v rvecvector) AtVec(i int) float64 {
+	L, U := v.x.Ix()
+	if i < 0 || i > U-L {
+		panic(errioob)
+	}
+	return v.x.E(L + i)
+}
@@ -0,0 +1,311 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+import (
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+)
+
+// jsonf -- a float64 that marshals the non-finite values as the JSON strings
+// "NaN", "+Inf", "-Inf".
+type jsonf float64
+
+func (f jsonf) MarshalJSON() ([]byte, error) {
+	x := float64(f)
+	if FiniteIs(x) {
+		return []byte(strconv.FormatFloat(x, 'g', -1, 64)), nil
+	}
+	return []byte(`"` + strconv.FormatFloat(x, 'g', -1, 64) + `"`), nil
+}
+
+func (f *jsonf) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
+		switch s {
+		case "NaN", "+Inf", "-Inf":
+		default:
+			return errors.New("mym.RVec.UnmarshalJSON: invalid number " + strconv.Quote(s))
+		}
+	}
+	x, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return errors.New("mym.RVec.UnmarshalJSON: invalid number " + strconv.Quote(s))
+	}
+	*f = jsonf(x)
+	return nil
+}
+
+// rvecjson -- the JSON representation of RVec: {"lwb":L,"vec":[...]} in the dense
+// mode, and {"lwb":L,"upb":U,"six":[...],"sval":[...]} in the sparse mode.
+// An empty vector is {"lwb":0}.
+type rvecjson struct {
+	Lwb  int     `json:"lwb"`
+	Upb  *int    `json:"upb,omitempty"`
+	Vec  []jsonf `json:"vec,omitempty"`
+	Six  []int   `json:"six,omitempty"`
+	Sval []jsonf `json:"sval,omitempty"`
+}
+
+// MarshalJSON -- implements json.Marshaler. The index bounds are stored
+// explicitly; the non-finite elements are stored as the strings "NaN", "+Inf"
+// and "-Inf"; a sparse vector stores only the nonzero elements.
+func (x RVec) MarshalJSON() ([]byte, error) {
+	L, U := x.Ix()
+	var r rvecjson
+	switch {
+	case U < L:
+	case x.sparse:
+		r.Lwb, r.Upb = L, &U
+		r.Six = x.six
+		r.Sval = make([]jsonf, len(x.sval))
+		for k, e := range x.sval {
+			r.Sval[k] = jsonf(e)
+		}
+	default:
+		r.Lwb = L
+		r.Vec = make([]jsonf, len(x.vec))
+		for i, e := range x.vec {
+			r.Vec[i] = jsonf(e)
+		}
+	}
+	return json.Marshal(r)
+}
+
+// UnmarshalJSON -- implements json.Unmarshaler (see MarshalJSON).
+func (x *RVec) UnmarshalJSON(b []byte) error {
+	var r rvecjson
+	if err := json.Unmarshal(b, &r); err != nil {
+		return err
+	}
+	if r.Upb == nil {
+		if len(r.Vec) == 0 {
+			*x = RVec{}
+			return nil
+		}
+		// len(r.Vec)-1 is compared before the addition to avoid an overflow
+		if r.Lwb < Mindex || r.Lwb > Maxdex || len(r.Vec)-1 > Maxdex-r.Lwb {
+			return errors.New("mym.RVec.UnmarshalJSON: " + erriib)
+		}
+		*x = rvecgen(r.Lwb, len(r.Vec), func(i int) float64 { return float64(r.Vec[i]) })
+		return nil
+	}
+	sval := make([]float64, len(r.Sval))
+	for k, e := range r.Sval {
+		sval[k] = float64(e)
+	}
+	z, err := sparseof(r.Lwb, *r.Upb, r.Six, sval)
+	if err != nil {
+		return errors.New("mym.RVec.UnmarshalJSON: " + err.Error())
+	}
+	*x = z
+	return nil
+}
+
+// sparseof -- returns the sparse vector with the given index bounds and nonzero elements.
+func sparseof(lwb, upb int, six []int, sval []float64) (RVec, error) {
+	if lwb < Mindex || upb > Maxdex || upb < lwb {
+		return RVec{}, errors.New(erriib)
+	}
+	if len(six) != len(sval) {
+		return RVec{}, errors.New("invalid sparse data")
+	}
+	z := RVec{sparse: true, lwb: lwb, upb: upb}
+	for k, i := range six {
+		if i < lwb || i > upb || (k > 0 && i <= six[k-1]) {
+			return RVec{}, errors.New(errioob)
+		}
+		if sval[k] != 0 {
+			z.six = append(z.six, i)
+			z.sval = append(z.sval, sval[k])
+		}
+	}
+	return z, nil
+}
+
+// rvecbinver -- the version of the binary format of RVec.
+const rvecbinver = 1
+
+// MarshalBinary -- implements encoding.BinaryMarshaler. The format (little-endian)
+// is the version byte 1, the mode byte (0 for dense, 1 for sparse), and
+//
+//	dense:  int64 lwb, int64 n, n float64 elements
+//	sparse: int64 lwb, int64 upb, int64 nnz, nnz pairs (int64 index, float64 element)
+//
+// An empty vector is stored as dense with lwb=0 and n=0.
+func (x RVec) MarshalBinary() ([]byte, error) {
+	L, U := x.Ix()
+	var b []byte
+	put := func(u uint64) {
+		var w [8]byte
+		binary.LittleEndian.PutUint64(w[:], u)
+		b = append(b, w[:]...)
+	}
+	switch {
+	case U < L:
+		b = append(b, rvecbinver, 0)
+		put(0)
+		put(0)
+	case x.sparse:
+		b = make([]byte, 0, 2+24+16*len(x.six))
+		b = append(b, rvecbinver, 1)
+		put(uint64(L))
+		put(uint64(U))
+		put(uint64(len(x.six)))
+		for k, i := range x.six {
+			put(uint64(i))
+			put(math.Float64bits(x.sval[k]))
+		}
+	default:
+		b = make([]byte, 0, 2+16+8*len(x.vec))
+		b = append(b, rvecbinver, 0)
+		put(uint64(L))
+		put(uint64(len(x.vec)))
+		for _, e := range x.vec {
+			put(math.Float64bits(e))
+		}
+	}
+	return b, nil
+}
+
+// UnmarshalBinary -- implements encoding.BinaryUnmarshaler (see MarshalBinary).
+func (x *RVec) UnmarshalBinary(b []byte) error {
+	errfmt := errors.New("mym.RVec.UnmarshalBinary: invalid data")
+	if len(b) < 2+16 || b[0] != rvecbinver || b[1] > 1 {
+		return errfmt
+	}
+	sparse := b[1] == 1
+	b = b[2:]
+	get := func() int64 {
+		u := binary.LittleEndian.Uint64(b)
+		b = b[8:]
+		return int64(u)
+	}
+	lwb := get()
+	if !sparse {
+		n := get()
+		if n < 0 || n > int64(len(b)/8) || int64(len(b)) != 8*n {
+			return errfmt
+		}
+		if n == 0 {
+			*x = RVec{}
+			return nil
+		}
+		if lwb < Mindex || lwb > Maxdex || n-1 > Maxdex-lwb {
+			return errors.New("mym.RVec.UnmarshalBinary: " + erriib)
+		}
+		*x = rvecgen(int(lwb), int(n), func(int) float64 { return math.Float64frombits(uint64(get())) })
+		return nil
+	}
+	if len(b) < 16 {
+		return errfmt
+	}
+	upb := get()
+	nnz := get()
+	if nnz < 0 || nnz > int64(len(b)/16) || int64(len(b)) != 16*nnz {
+		return errfmt
+	}
+	if lwb < Mindex || upb > Maxdex {
+		return errors.New("mym.RVec.UnmarshalBinary: " + erriib)
+	}
+	six := make([]int, nnz)
+	sval := make([]float64, nnz)
+	for k := range six {
+		six[k] = int(get())
+		sval[k] = math.Float64frombits(uint64(get()))
+	}
+	z, err := sparseof(int(lwb), int(upb), six, sval)
+	if err != nil {
+		return errors.New("mym.RVec.UnmarshalBinary: " + err.Error())
+	}
+	*x = z
+	return nil
+}
+
+// WriteCSV -- writes `x` to `w` in the CSV format: the header row "index,value"
+// followed by the rows "i,x[i]". A dense vector writes all its elements; a sparse
+// vector writes its nonzero elements and the zero elements at its index bounds,
+// so that ReadCSV restores the index bounds.
+func (x RVec) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"index", "value"}); err != nil {
+		return err
+	}
+	row := func(i int, e float64) error {
+		return cw.Write([]string{strconv.Itoa(i), strconv.FormatFloat(e, 'g', -1, 64)})
+	}
+	L, U := x.Ix()
+	switch {
+	case U < L:
+	case x.sparse:
+		if x.spget(L) == 0 {
+			if err := row(L, 0); err != nil {
+				return err
+			}
+		}
+		for k, i := range x.six {
+			if err := row(i, x.sval[k]); err != nil {
+				return err
+			}
+		}
+		if U != L && x.spget(U) == 0 {
+			if err := row(U, 0); err != nil {
+				return err
+			}
+		}
+	default:
+		for i, e := range x.vec {
+			if err := row(L+i, e); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadCSV -- reads a vector written by WriteCSV from `r`. The rows may be in any
+// order; the index bounds are the smallest and largest indices read, and
+// the missing elements are zero. The vector is sparse if its index range
+// is longer than 2^24.
+func ReadCSV(r io.Reader) (RVec, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 2
+	head, err := cr.Read()
+	if err == io.EOF {
+		return RVec{}, errors.New("mym.ReadCSV: missing header")
+	}
+	if err != nil {
+		return RVec{}, err
+	}
+	if head[0] != "index" || head[1] != "value" {
+		return RVec{}, errors.New("mym.ReadCSV: invalid header")
+	}
+	var x RVec
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return x, nil
+		}
+		if err != nil {
+			return RVec{}, err
+		}
+		i, err := strconv.Atoi(rec[0])
+		if err != nil || i < Mindex || i > Maxdex {
+			return RVec{}, errors.New("mym.ReadCSV: invalid index " + strconv.Quote(rec[0]))
+		}
+		e, err := strconv.ParseFloat(rec[1], 64)
+		if err != nil {
+			return RVec{}, errors.New("mym.ReadCSV: invalid value " + strconv.Quote(rec[1]))
+		}
+		x.U(i, e)
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+// rveceq -- returns true iff `x` and `y` have the same mode, index bounds and
+// elements (NaNs are equal).
+func rveceq(x, y RVec) bool {
+	xL, xU := x.Ix()
+	yL, yU := y.Ix()
+	if x.IsSparse() != y.IsSparse() || xL != yL || xU != yU {
+		return false
+	}
+	xi, xv := x.nonzeros()
+	yi, yv := y.nonzeros()
+	if len(xi) != len(yi) {
+		return false
+	}
+	for k := range xi {
+		if xi[k] != yi[k] || !f64EQ(xv[k], yv[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRVecIO(t *testing.T) {
+	dense := Genrvec(-3, 2)
+	dense.U(-3, 1.5)
+	dense.U(-1, math.NaN())
+	dense.U(0, math.Inf(-1))
+	dense.U(2, -1e-300)
+	var sparse RVec
+	sparse.U(-1e12, 0)
+	sparse.U(5, math.Inf(1))
+	sparse.U(7, math.Pi)
+	sparse.U(1e12, 0)
+	for k, x := range []RVec{{}, dense, sparse, Genrvec(4, 4)} {
+		b, err := json.Marshal(x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var y RVec
+		if err := json.Unmarshal(b, &y); err != nil || !rveceq(x, y) {
+			t.Fatalf("%d: json %s: %v", k, b, err)
+		}
+		//
+		b, err = x.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var z RVec
+		if err := z.UnmarshalBinary(b); err != nil || !rveceq(x, z) {
+			t.Fatalf("%d: binary: %v", k, err)
+		}
+		//
+		var buf bytes.Buffer
+		if err := x.WriteCSV(&buf); err != nil {
+			t.Fatal(err)
+		}
+		w, err := ReadCSV(&buf)
+		if err != nil || !rveceq(x, w) {
+			t.Fatalf("%d: csv: %v", k, err)
+		}
+	}
+	//
+	b, _ := json.Marshal(dense)
+	if string(b) != `{"lwb":-3,"vec":[1.5,0,"NaN","-Inf",0,-1e-300]}` {
+		t.Fatalf("json: %s", b)
+	}
+	b, _ = json.Marshal(sparse)
+	if string(b) != `{"lwb":-1000000000000,"upb":1000000000000,"six":[5,7],"sval":["+Inf",3.141592653589793]}` {
+		t.Fatalf("json: %s", b)
+	}
+	//
+	var x RVec
+	for _, s := range []string{
+		`{"lwb":0,"vec":["inf"]}`,
+		`{"lwb":0,"upb":5,"six":[3,2],"sval":[1,1]}`,
+		`{"lwb":0,"upb":5,"six":[6],"sval":[1]}`,
+		`{"lwb":0,"upb":5,"six":[1],"sval":[]}`,
+		`{"lwb":9223372036854775807,"vec":[1,2]}`,
+		`{"lwb":281474976710656,"vec":[1,2]}`,
+		`{"lwb":-9223372036854775808,"vec":[1,2]}`,
+		`{"lwb":9223372036854775807,"upb":9223372036854775807,"six":[],"sval":[]}`,
+	} {
+		if err := json.Unmarshal([]byte(s), &x); err == nil {
+			t.Fatalf("json: no error for %s", s)
+		}
+	}
+	if err := x.UnmarshalBinary([]byte{1, 0, 1, 2, 3}); err == nil {
+		t.Fatal("binary: no error")
+	}
+	for _, lwb := range []int64{math.MaxInt64, Maxdex, math.MinInt64, Mindex - 1} {
+		b := []byte{1, 0}
+		for _, u := range []uint64{uint64(lwb), 2, math.Float64bits(1), math.Float64bits(2)} {
+			var w [8]byte
+			binary.LittleEndian.PutUint64(w[:], u)
+			b = append(b, w[:]...)
+		}
+		if err := x.UnmarshalBinary(b); err == nil {
+			t.Fatalf("binary: no error for lwb=%v", lwb)
+		}
+	}
+	for _, s := range []string{"", "i,v\n", "index,value\nx,1\n", "index,value\n1,y\n", "index,value\n1\n"} {
+		if _, err := ReadCSV(strings.NewReader(s)); err == nil {
+			t.Fatalf("csv: no error for %q", s)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+// Map -- returns the vector z[i]=f(x[i]) with the index bounds of `x`.
+// For a sparse `x`, `f` is applied only to the nonzero elements.
+func (x RVec) Map(f func(float64) float64) RVec {
+	if x.sparse {
+		return x.spmap(f)
+	}
+	L, U := x.Ix()
+	z := Genrvec(L, U)
+	for i, e := range x.vec {
+		z.vec[i] = f(e)
+	}
+	return z
+}
+
+// Zip -- returns the vector z[i]=f(x[i],y[i]) for the union of index bounds
+// of `x` and `y`. If `x` or `y` is sparse, or the union is longer than 2^24,
+// the result is sparse and `f` is applied only where x[i] or y[i] is nonzero.
+func (x RVec) Zip(y RVec, f func(a, b float64) float64) RVec {
+	L, U := x.span(y)
+	if U < L {
+		return RVec{}
+	}
+	if x.sparse || y.sparse || U-L+1 > sparsemax {
+		return x.spmerge(y, f)
+	}
+	z := Genrvec(L, U)
+	xL, xU := x.Ix()
+	yL, yU := y.Ix()
+	for i := L; i <= U; i++ {
+		a, b := 0.0, 0.0
+		if xL <= i && i <= xU {
+			a = x.vec[i-xL]
+		}
+		if yL <= i && i <= yU {
+			b = y.vec[i-yL]
+		}
+		z.vec[i-L] = f(a, b)
+	}
+	return z
+}
+
+// AddTo -- updates x=x+y in place, extending the index bounds of `x` if necessary.
+func (x *RVec) AddTo(y RVec) {
+	x.Axpy(1, y)
+}
+
+// Axpy -- updates x=x+a·y in place, extending the index bounds of `x` if necessary.
+func (x *RVec) Axpy(a float64, y RVec) {
+	yL, yU := y.Ix()
+	if yU < yL {
+		return
+	}
+	L, U := x.span(y)
+	if x.sparse || y.sparse || U-L+1 > sparsemax {
+		*x = x.spmerge(y, func(e, f float64) float64 { return e + a*f })
+		return
+	}
+	x.Reserve(L, U)
+	x.expose(L, U)
+	z := x.vec[yL-L:]
+	for i, e := range y.vec {
+		z[i] += a * e
+	}
+}
+
+// Scale -- updates x=s·x in place.
+func (x *RVec) Scale(s float64) {
+	if x.sparse {
+		*x = x.spmap(func(e float64) float64 { return e * s })
+		return
+	}
+	for i := range x.vec {
+		x.vec[i] *= s
+	}
+}
+
+// View -- returns the vector with the elements x[lo],...,x[hi] (restricted to
+// the index bounds of `x`) that shares the storage with `x`: updates of these
+// elements through the view are visible in `x` and vice versa. Extending
+// the index bounds of the view detaches it from `x`. For a sparse `x`,
+// View returns a copy of the elements.
+func (x RVec) View(lo, hi int) RVec {
+	L, U := x.Ix()
+	lo, hi = imax(lo, L), imin(hi, U)
+	if hi < lo {
+		return RVec{}
+	}
+	if x.sparse {
+		i, _ := x.spsearch(lo)
+		j, _ := x.spsearch(hi + 1)
+		z := RVec{sparse: true, lwb: lo, upb: hi}
+		z.six = append([]int(nil), x.six[i:j]...)
+		z.sval = append([]float64(nil), x.sval[i:j]...)
+		return z
+	}
+	v := x.vec[lo-L : hi-L+1 : hi-L+1]
+	return denseof(lo, v)
+}
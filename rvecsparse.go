@@ -0,0 +1,234 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+import (
+	"sort"
+)
+
+// sparsemax -- the maximum length of the index range of a dense vector.
+// Genrvec and U switch to the sparse mode for longer index ranges.
+const sparsemax = 1 << 24
+
+const errdense = "index range too large for the dense mode"
+
+// IsSparse -- returns true iff `x` is in the sparse mode.
+//
+// In the sparse mode, a vector stores its index bounds and only the nonzero
+// elements as sorted index/value lists. Updating the elements in increasing
+// index order takes amortized constant time per element; other insertions and
+// deletions of nonzero elements take O(nnz) time. All methods accept vectors
+// in both modes.
+// Element-wise operations on a sparse vector (Mul, Div, ...) do not change its
+// implicit zero elements, so e.g. a division by zero does not produce NaNs there.
+func (x RVec) IsSparse() bool {
+	return x.sparse
+}
+
+// Sparse -- returns a copy of `x` in the sparse mode.
+func (x RVec) Sparse() RVec {
+	if x.sparse {
+		return x.Copy()
+	}
+	L, U := x.Ix()
+	if U < L {
+		return RVec{}
+	}
+	z := RVec{sparse: true, lwb: L, upb: U}
+	for i, e := range x.vec {
+		if e != 0 {
+			z.six = append(z.six, L+i)
+			z.sval = append(z.sval, e)
+		}
+	}
+	return z
+}
+
+// Dense -- returns a copy of `x` in the dense mode.
+// Panics if the index range of `x` is longer than 2^24.
+func (x RVec) Dense() RVec {
+	if !x.sparse {
+		return x.Copy()
+	}
+	if x.upb-x.lwb+1 > sparsemax {
+		panic(errdense)
+	}
+	buf := make([]float64, x.upb-x.lwb+1)
+	for k, i := range x.six {
+		buf[i-x.lwb] = x.sval[k]
+	}
+	return denseof(x.lwb, buf)
+}
+
+// spsearch -- returns the position of the index `i` in the index list of
+// a sparse vector `x`, and true iff the element x[i] is stored.
+func (x RVec) spsearch(i int) (int, bool) {
+	k := sort.SearchInts(x.six, i)
+	return k, k < len(x.six) && x.six[k] == i
+}
+
+// spget -- returns the element x[i] of a sparse vector `x`.
+func (x RVec) spget(i int) float64 {
+	if k, ok := x.spsearch(i); ok {
+		return x.sval[k]
+	}
+	return 0
+}
+
+// spset -- updates the element x[i]=e of a sparse vector `x`
+// and extends the index bounds of `x` if necessary.
+func (x *RVec) spset(i int, e float64) {
+	x.lwb, x.upb = imin(x.lwb, i), imax(x.upb, i)
+	k, ok := x.spsearch(i)
+	n := len(x.six)
+	switch {
+	case ok && e != 0:
+		x.sval[k] = e
+	case ok && k == n-1:
+		// the claimed length is not changed, so that the next append reallocates
+		x.six, x.sval = x.six[:k], x.sval[:k]
+	case ok:
+		// new arrays are allocated, since the old ones may be shared by copies of `x`
+		x.spalloc(n-1, k, k+1)
+	case e == 0:
+	case k == n && x.snz != nil && *x.snz == n && n < cap(x.six) && n < cap(x.sval):
+		// append in place: no copy of `x` uses the elements beyond n
+		x.six, x.sval = append(x.six, i), append(x.sval, e)
+		*x.snz = n + 1
+	default:
+		x.spalloc(n+1+n/2, k, k)
+		x.six, x.sval = x.six[:n+1], x.sval[:n+1]
+		copy(x.six[k+1:], x.six[k:n])
+		copy(x.sval[k+1:], x.sval[k:n])
+		x.six[k], x.sval[k] = i, e
+		*x.snz = n + 1
+	}
+}
+
+// spalloc -- moves the nonzero elements of a sparse vector `x` except those at
+// the positions k1...k2-1 to new arrays with the capacity `c`.
+func (x *RVec) spalloc(c, k1, k2 int) {
+	n := len(x.six) - (k2 - k1)
+	six, sval := make([]int, n, imax(c, n)), make([]float64, n, imax(c, n))
+	copy(six, x.six[:k1])
+	copy(six[k1:], x.six[k2:])
+	copy(sval, x.sval[:k1])
+	copy(sval[k1:], x.sval[k2:])
+	x.six, x.sval, x.snz = six, sval, &n
+}
+
+// nonzeros -- returns the sorted indices and the values of nonzero elements of `x`.
+func (x RVec) nonzeros() (six []int, sval []float64) {
+	if x.sparse {
+		return x.six, x.sval
+	}
+	L, _ := x.Ix()
+	for i, e := range x.vec {
+		if e != 0 {
+			six = append(six, L+i)
+			sval = append(sval, e)
+		}
+	}
+	return
+}
+
+// spmap -- returns the sparse vector with the elements f(x[i]) for
+// the nonzero elements x[i] of a sparse vector `x`.
+func (x RVec) spmap(f func(float64) float64) RVec {
+	z := RVec{sparse: true, lwb: x.lwb, upb: x.upb}
+	z.six = make([]int, 0, len(x.six))
+	z.sval = make([]float64, 0, len(x.six))
+	for k, i := range x.six {
+		if e := f(x.sval[k]); e != 0 {
+			z.six = append(z.six, i)
+			z.sval = append(z.sval, e)
+		}
+	}
+	return z
+}
+
+// spmerge -- returns the sparse vector with the elements f(x[i],y[i])
+// for the union of index bounds of `x` and `y`; f(0,0) must be zero.
+func (x RVec) spmerge(y RVec, f func(a, b float64) float64) RVec {
+	L, U := x.span(y)
+	if U < L {
+		return RVec{}
+	}
+	z := RVec{sparse: true, lwb: L, upb: U}
+	xi, xv := x.nonzeros()
+	yi, yv := y.nonzeros()
+	add := func(i int, e float64) {
+		if e != 0 {
+			z.six = append(z.six, i)
+			z.sval = append(z.sval, e)
+		}
+	}
+	j, k := 0, 0
+	for j < len(xi) && k < len(yi) {
+		switch {
+		case xi[j] < yi[k]:
+			add(xi[j], f(xv[j], 0))
+			j++
+		case xi[j] > yi[k]:
+			add(yi[k], f(0, yv[k]))
+			k++
+		default:
+			add(xi[j], f(xv[j], yv[k]))
+			j++
+			k++
+		}
+	}
+	for ; j < len(xi); j++ {
+		add(xi[j], f(xv[j], 0))
+	}
+	for ; k < len(yi); k++ {
+		add(yi[k], f(0, yv[k]))
+	}
+	return z
+}
+
+// spdot -- returns the scalar (dot) product of `x` and `y`, where `x` or `y` is sparse.
+func (x RVec) spdot(y RVec) float64 {
+	if !x.sparse {
+		x, y = y, x
+	}
+	var a Accu
+	if y.sparse {
+		j, k := 0, 0
+		for j < len(x.six) && k < len(y.six) {
+			switch {
+			case x.six[j] < y.six[k]:
+				j++
+			case x.six[j] > y.six[k]:
+				k++
+			default:
+				a.AddProduct(x.sval[j], y.sval[k])
+				j++
+				k++
+			}
+		}
+		return a.Value()
+	}
+	for k, i := range x.six {
+		a.AddProduct(x.sval[k], y.E(i))
+	}
+	return a.Value()
+}
+
+// spgap -- returns the smallest index `i` of an implicit zero
+// element x[i] of a sparse vector `x`, if any.
+func (x RVec) spgap() (int, bool) {
+	if len(x.six) == x.upb-x.lwb+1 {
+		return 0, false
+	}
+	i := x.lwb
+	for _, j := range x.six {
+		if j != i {
+			break
+		}
+		i++
+	}
+	return i, true
+}
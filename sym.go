@@ -0,0 +1,47 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+// Sym -- represents a symmetric matrix.
+type Sym struct {
+	n int
+	a []float64
+}
+
+// NewSym -- returns an n-by-n symmetric matrix with all zero elements.
+// The matrix is empty if n=0.
+func NewSym(n int) Sym {
+	if n < 0 {
+		panic("mym.NewSym: n < 0")
+	}
+	return Sym{n, make([]float64, n*(n+1)/2)}
+}
+
+// N -- returns the matrix size.
+func (a Sym) N() int {
+	return a.n
+}
+
+// Get -- returns a[i,j].
+func (a Sym) Get(i, j int) float64 {
+	if !(0 <= i && i < a.n && 0 <= j && j < a.n) {
+		panic("mym.Sym.Get: index")
+	}
+	if i > j {
+		i, j = j, i
+	}
+	return a.a[i*a.n-(i-1)*i/2+j-i]
+}
+
+// Set -- assigns a[i,j] = a[j,i] = x.
+func (a Sym) Set(i, j int, x float64) {
+	if !(0 <= i && i < a.n && 0 <= j && j < a.n) {
+		panic("mym.Sym.Set: index")
+	}
+	if i > j {
+		i, j = j, i
+	}
+	a.a[i*a.n-(i-1)*i/2+j-i] = x
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+import (
+	"testing"
+)
+
+func TestSym(t *testing.T) {
+	const n = 7
+	a := NewSym(n)
+	if a.N() != n {
+		t.Fatalf("N()=%v", a.N())
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j <= i; j++ {
+			a.Set(i, j, float64(10*i+j))
+		}
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			want := float64(10*imax(i, j) + imin(i, j))
+			if a.Get(i, j) != want {
+				t.Fatalf("a[%d,%d]=%v, want %v", i, j, a.Get(i, j), want)
+			}
+		}
+	}
+	if e := NewSym(0); e.N() != 0 {
+		t.Fatalf("NewSym(0).N()=%v", e.N())
+	}
+	for _, f := range []func(){
+		func() { NewSym(-1) },
+		func() { a.Get(n, 0) },
+		func() { a.Get(0, -1) },
+		func() { a.Set(-1, 0, 1) },
+		func() { NewSym(0).Get(0, 0) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("no panic")
+				}
+			}()
+			f()
+		}()
+	}
+}
@@ -40,8 +40,8 @@ func TauEstim(x []float64) (μ, σ float64) {
 	for i := range w {
 		w[i] = fnW(c1, (x[i]-μ0)/σ0)
 	}
-	μ = AccuDot(n, func(i int) float64 { return x[i] }, func(i int) float64 { return w[i] })
-	μ /= AccuSum(n, func(i int) float64 { return w[i] })
+	μ = AccuDotSlice(x, w)
+	μ /= AccuSumSlice(w)
 	//
 	const c2 = 3.0
 	σ = AccuSum(n, func(i int) float64 { return fnR(c2, (x[i]-μ)/σ0) })
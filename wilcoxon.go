@@ -0,0 +1,232 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+import (
+	"math"
+	"sort"
+)
+
+// Alternative -- specifies the alternative hypothesis of a statistical test.
+type Alternative int
+
+const (
+	// TwoSided -- the location shift is not equal to zero.
+	TwoSided Alternative = iota
+	// Less -- the location shift is less than zero.
+	Less
+	// Greater -- the location shift is greater than zero.
+	Greater
+)
+
+// maxexact -- the sample size limit for the exact distributions of
+// the Wilcoxon and Mann–Whitney statistics.
+const maxexact = 50
+
+// WilcoxonSR -- performs the Wilcoxon signed-rank test of the null hypothesis
+// that the distribution of x[i]-μ is symmetric about zero. Returns the statistic
+// V (the sum of ranks of the positive differences), the exact p-value, and
+// the p-value based on the normal approximation with continuity and tie
+// corrections. Zero differences are discarded. The exact p-value is NaN
+// when there are zeros or ties, or when there are 50 or more differences.
+func WilcoxonSR(x []float64, μ float64, alt Alternative) (V, pexact, papprox float64) {
+	d := make([]float64, 0, len(x))
+	zeros := false
+	for _, xi := range x {
+		if xi == μ {
+			zeros = true
+			continue
+		}
+		d = append(d, xi-μ)
+	}
+	n := len(d)
+	if n == 0 {
+		V, pexact, papprox = 0, math.NaN(), math.NaN()
+		return
+	}
+	//
+	a := make([]float64, n)
+	for i, di := range d {
+		a[i] = math.Abs(di)
+	}
+	r, tcorr := midranks(a)
+	for i, di := range d {
+		if di > 0 {
+			V += r[i]
+		}
+	}
+	//
+	pexact = math.NaN()
+	if !zeros && tcorr == 0 && n < maxexact {
+		pexact = exactp(signrankdist(n), V, alt)
+	}
+	//
+	nf := float64(n)
+	mean := nf * (nf + 1) / 4
+	sd := math.Sqrt(nf*(nf+1)*(2*nf+1)/24 - tcorr/48)
+	papprox = approxp(V-mean, sd, alt)
+	return
+}
+
+// MannWhitney -- performs the Mann–Whitney U test (the Wilcoxon rank-sum test)
+// of the null hypothesis that the distributions of `x` and `y` are equal.
+// Returns the statistic U (the number of pairs with x[i]>y[j], ties counted
+// as 1/2), the exact p-value, and the p-value based on the normal approximation
+// with continuity and tie corrections. The exact p-value is NaN when there are
+// ties, or when either sample has 50 or more elements.
+func MannWhitney(x, y []float64, alt Alternative) (U, pexact, papprox float64) {
+	m, n := len(x), len(y)
+	if m == 0 || n == 0 {
+		U, pexact, papprox = 0, math.NaN(), math.NaN()
+		return
+	}
+	//
+	xy := make([]float64, m+n)
+	copy(xy, x)
+	copy(xy[m:], y)
+	r, tcorr := midranks(xy)
+	U = AccuSumSlice(r[:m])
+	U -= float64(m) * float64(m+1) / 2
+	//
+	pexact = math.NaN()
+	if tcorr == 0 && m < maxexact && n < maxexact {
+		pexact = exactp(ranksumdist(m, n), U, alt)
+	}
+	//
+	mf, nf := float64(m), float64(n)
+	N := mf + nf
+	mean := mf * nf / 2
+	sd := math.Sqrt(mf * nf / 12 * ((N + 1) - tcorr/(N*(N-1))))
+	papprox = approxp(U-mean, sd, alt)
+	return
+}
+
+// midranks -- returns the ranks of `x` (ties receive the average rank)
+// and the tie correction term Σ(t³-t) over the groups of t tied elements.
+func midranks(x []float64) (r []float64, tcorr float64) {
+	n := len(x)
+	ix := make([]int, n)
+	for i := range ix {
+		ix[i] = i
+	}
+	sort.Slice(ix, func(i, j int) bool { return f64LT(x[ix[i]], x[ix[j]]) })
+	//
+	r = make([]float64, n)
+	for i := 0; i < n; {
+		j := i + 1
+		for j < n && f64EQ(x[ix[j]], x[ix[i]]) {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			r[ix[k]] = rank
+		}
+		if t := float64(j - i); t > 1 {
+			tcorr += t*t*t - t
+		}
+		i = j
+	}
+	return
+}
+
+// signrankdist -- returns the exact null distribution of the signed-rank
+// statistic: P[v] is the probability of V=v, v=0,1,...,n(n+1)/2.
+func signrankdist(n int) []float64 {
+	N := n * (n + 1) / 2
+	P := make([]float64, N+1)
+	P[0] = 1
+	top := 0
+	for k := 1; k <= n; k++ {
+		top += k
+		for v := top; v >= k; v-- {
+			P[v] = (P[v] + P[v-k]) / 2
+		}
+		for v := k - 1; v >= 0; v-- {
+			P[v] /= 2
+		}
+	}
+	return P
+}
+
+// ranksumdist -- returns the exact null distribution of the Mann–Whitney
+// statistic: P[u] is the probability of U=u, u=0,1,...,mn.
+func ranksumdist(m, n int) []float64 {
+	// P(u;i,j) = i/(i+j)·P(u-j;i-1,j) + j/(i+j)·P(u;i,j-1)
+	N := m * n
+	prev := make([][]float64, m+1)
+	next := make([][]float64, m+1)
+	for i := 0; i <= m; i++ {
+		prev[i] = make([]float64, N+1)
+		next[i] = make([]float64, N+1)
+		prev[i][0] = 1 // j=0
+	}
+	for j := 1; j <= n; j++ {
+		next[0][0] = 1
+		for i := 1; i <= m; i++ {
+			wi, wj := float64(i)/float64(i+j), float64(j)/float64(i+j)
+			for u := 0; u <= i*j; u++ {
+				p := wj * prev[i][u]
+				if u >= j {
+					p += wi * next[i-1][u-j]
+				}
+				next[i][u] = p
+			}
+		}
+		prev, next = next, prev
+	}
+	return prev[m]
+}
+
+// exactp -- returns the p-value of the statistic `s` given its discrete
+// null distribution `P` on 0,1,...,len(P)-1 (symmetric about its mean).
+func exactp(P []float64, s float64, alt Alternative) float64 {
+	N := len(P)
+	sum := func(lo, hi int) float64 { // P[lo≤S≤hi]
+		lo, hi = imax(lo, 0), imin(hi, N-1)
+		if lo > hi {
+			return 0
+		}
+		return AccuSumSlice(P[lo : hi+1])
+	}
+	lower := sum(0, int(math.Floor(s)))
+	upper := sum(int(math.Ceil(s)), N-1)
+	var p float64
+	switch alt {
+	case Less:
+		p = lower
+	case Greater:
+		p = upper
+	default:
+		p = 2 * math.Min(lower, upper)
+	}
+	return math.Min(p, 1)
+}
+
+// approxp -- returns the p-value of the centered statistic `z` with
+// the standard deviation `sd` using the normal approximation.
+func approxp(z, sd float64, alt Alternative) float64 {
+	var c float64
+	switch alt {
+	case Less:
+		c = -0.5
+	case Greater:
+		c = 0.5
+	default:
+		if z > 0 {
+			c = 0.5
+		} else if z < 0 {
+			c = -0.5
+		}
+	}
+	z = (z - c) / sd
+	switch alt {
+	case Less:
+		return NormCDF(z)
+	case Greater:
+		return NormCDF(-z)
+	default:
+		return math.Min(1, 2*NormCDF(-math.Abs(z)))
+	}
+}
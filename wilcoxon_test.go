@@ -0,0 +1,71 @@
+// Copyright (c) 2021 Leonid Kneller. All rights reserved.
+// Licensed under the MIT license.
+// See the LICENSE file for full license information.
+
+package mym
+
+import (
+	"math"
+	"testing"
+)
+
+// TestHodgesLehmann checks if HodgesLehmann and HodgesLehmann2 agree with
+// the medians of all Walsh averages and all pairwise differences.
+func TestHodgesLehmann(t *testing.T) {
+	for n := 1; n <= 40; n++ {
+		x := make([]float64, n)
+		y := make([]float64, n+3)
+		for i := range x {
+			x[i] = math.Round(10 * N01())
+		}
+		for j := range y {
+			y[j] = math.Round(10 * N01())
+		}
+		//
+		var w []float64
+		for i := 0; i < n; i++ {
+			for j := i; j < n; j++ {
+				w = append(w, (x[i]+x[j])/2)
+			}
+		}
+		lo, hi := Medians(w)
+		if hl := HodgesLehmann(x); hl != lo+(hi-lo)/2 {
+			t.Fatalf("hodgeslehmann: n=%v, hl=%v, want=%v", n, hl, lo+(hi-lo)/2)
+		}
+		//
+		var d []float64
+		for i := range x {
+			for j := range y {
+				d = append(d, x[i]-y[j])
+			}
+		}
+		lo, hi = Medians(d)
+		if hl := HodgesLehmann2(x, y); hl != lo+(hi-lo)/2 {
+			t.Fatalf("hodgeslehmann2: n=%v, hl=%v, want=%v", n, hl, lo+(hi-lo)/2)
+		}
+	}
+}
+
+// TestWilcoxon checks WilcoxonSR and MannWhitney against the examples
+// from the R documentation of `wilcox.test`.
+func TestWilcoxon(t *testing.T) {
+	const tol = 1.0e-4
+	//
+	x := []float64{1.83, 0.50, 1.62, 2.48, 1.68, 1.88, 1.55, 3.06, 1.30}
+	y := []float64{0.878, 0.647, 0.598, 2.05, 1.06, 1.29, 1.06, 3.14, 1.29}
+	d := make([]float64, len(x))
+	for i := range d {
+		d[i] = x[i] - y[i]
+	}
+	V, p, _ := WilcoxonSR(d, 0, Greater)
+	if V != 40 || math.Abs(p-0.01953) > tol {
+		t.Fatalf("wilcoxonsr: V=%v, p=%v", V, p)
+	}
+	//
+	x = []float64{0.80, 0.83, 1.89, 1.04, 1.45, 1.38, 1.91, 1.64, 0.73, 1.46}
+	y = []float64{1.15, 0.88, 0.90, 0.74, 1.21}
+	U, p, _ := MannWhitney(x, y, Greater)
+	if U != 35 || math.Abs(p-0.1272) > tol {
+		t.Fatalf("mannwhitney: U=%v, p=%v", U, p)
+	}
+}